@@ -0,0 +1,239 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// ActorOption configures an Actor.
+type ActorOption func(*actorOptions)
+
+type actorOptions struct {
+	mailboxSize int
+	maxRestarts int
+}
+
+// WithMailboxSize sets the number of messages that may wait in the mailbox of an Actor.
+// Tell and Ask block while the mailbox is full. The default is 0: a message is accepted
+// only when the actor is ready to process it.
+func WithMailboxSize(size int) ActorOption {
+	return func(o *actorOptions) {
+		o.mailboxSize = max(size, 0)
+	}
+}
+
+// WithMaxRestarts stops an Actor once its handler has panicked more than n times.
+// The default is 0, no limit.
+func WithMaxRestarts(n int) ActorOption {
+	return func(o *actorOptions) {
+		o.maxRestarts = max(n, 0)
+	}
+}
+
+// Actor owns a piece of state and processes messages one at a time, in the order they arrive,
+// on a single goroutine. The state is never shared, so it needs no locking: where many goroutines
+// contend for a mutex-protected value, an actor that owns it is often simpler and faster.
+//
+// The state lives in the handler created by the newHandler function passed to NewActor.
+// If the handler panics, the message is answered with a *PanicError and the actor restarts:
+// it discards the handler and its state, and creates a fresh one with newHandler.
+//
+// All methods are safe for concurrent use by multiple goroutines.
+//
+// Example usage:
+//
+//	counter := async.NewActor(func() func(ctx context.Context, delta int) (int, error) {
+//		total := 0 // owned by the actor goroutine
+//		return func(ctx context.Context, delta int) (int, error) {
+//			total += delta
+//			return total, nil
+//		}
+//	}, async.WithMailboxSize(100))
+//	defer counter.Stop(context.Background())
+//
+//	counter.Tell(1)
+//	total, err := counter.Ask(2).Result() // 3
+type Actor[Msg, Reply any] struct {
+	newHandler  func() func(ctx context.Context, msg Msg) (Reply, error)
+	mailbox     chan envelope[Msg, Reply]
+	maxRestarts int
+
+	ctx    context.Context // canceled by StopNow or when the actor fails
+	cancel context.CancelFunc
+
+	mu          sync.RWMutex // guards closed and sends to mailbox
+	closed      bool
+	closing     chan struct{} // closed before close takes mu, to release blocked senders
+	closingOnce sync.Once
+	done        chan struct{}
+	err         error
+}
+
+type envelope[Msg, Reply any] struct {
+	msg   Msg
+	reply *state[Reply] // nil for Tell
+}
+
+// NewActor creates an Actor whose messages are handled by the function returned by newHandler,
+// and starts it. newHandler is called once at start and again on every restart.
+func NewActor[Msg, Reply any](newHandler func() func(ctx context.Context, msg Msg) (Reply, error), opts ...ActorOption) *Actor[Msg, Reply] {
+	var o actorOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	a := &Actor[Msg, Reply]{
+		newHandler:  newHandler,
+		mailbox:     make(chan envelope[Msg, Reply], o.mailboxSize),
+		maxRestarts: o.maxRestarts,
+		closing:     make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	a.ctx, a.cancel = context.WithCancel(context.Background())
+
+	go a.run()
+
+	return a
+}
+
+// Tell puts msg into the mailbox without waiting for it to be processed.
+// It returns ErrActorStopped if the actor is stopped.
+func (a *Actor[Msg, Reply]) Tell(msg Msg) error {
+	return a.send(envelope[Msg, Reply]{msg: msg})
+}
+
+// Ask puts msg into the mailbox and returns a Future for the reply of the handler.
+// If the actor is stopped, or stops before processing msg, the Future is settled with ErrActorStopped.
+func (a *Actor[Msg, Reply]) Ask(msg Msg) *Future[Reply] {
+	p := NewPromise[Reply]()
+
+	if err := a.send(envelope[Msg, Reply]{msg: msg, reply: p.state}); err != nil {
+		p.Reject(err)
+	}
+
+	return p.GetFuture()
+}
+
+// Stop stops accepting messages and waits until the messages already in the mailbox are processed
+// or ctx is done, in which case it returns ctx.Err(). Senders blocked on a full mailbox are
+// released with ErrActorStopped. Stop may be called multiple times.
+func (a *Actor[Msg, Reply]) Stop(ctx context.Context) error {
+	a.markClosing()
+	go a.close()
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StopNow stops accepting messages, cancels the context of the message being processed and
+// answers the messages left in the mailbox with ErrActorStopped without processing them.
+// It does not wait for the message being processed; call Stop for that.
+func (a *Actor[Msg, Reply]) StopNow() {
+	a.cancel() // release senders blocked on a full mailbox before taking the lock
+	a.close()
+}
+
+// Done returns a channel that is closed once the actor is stopped and has processed its last message.
+func (a *Actor[Msg, Reply]) Done() <-chan struct{} {
+	return a.done
+}
+
+// Err returns the *PanicError that stopped the actor once it exceeded WithMaxRestarts,
+// or nil if it was stopped by Stop or StopNow or is still running.
+func (a *Actor[Msg, Reply]) Err() error {
+	select {
+	case <-a.done:
+		return a.err
+	default:
+		return nil
+	}
+}
+
+func (a *Actor[Msg, Reply]) send(env envelope[Msg, Reply]) error {
+	select {
+	case <-a.closing:
+		return ErrActorStopped // do not wait for a pending close to take the lock
+	default:
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return ErrActorStopped
+	}
+
+	select {
+	case a.mailbox <- env:
+		return nil
+	case <-a.closing:
+		return ErrActorStopped
+	case <-a.ctx.Done():
+		return ErrActorStopped
+	}
+}
+
+// markClosing makes new and blocked senders fail without waiting for close to take the lock.
+func (a *Actor[Msg, Reply]) markClosing() {
+	a.closingOnce.Do(func() { close(a.closing) })
+}
+
+func (a *Actor[Msg, Reply]) close() {
+	a.markClosing()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.closed {
+		a.closed = true
+		close(a.mailbox)
+	}
+}
+
+func (a *Actor[Msg, Reply]) run() {
+	defer close(a.done)
+	defer a.cancel() // the mailbox is drained, release the context
+
+	var (
+		handle   = a.newHandler()
+		restarts int
+		zero     Reply
+	)
+
+	for env := range a.mailbox {
+		if a.ctx.Err() != nil {
+			if env.reply != nil {
+				env.reply.settle(zero, ErrActorStopped)
+			}
+			continue
+		}
+
+		panicked := true
+		v, err := withRecover(func() (Reply, error) {
+			v, err := handle(a.ctx, env.msg)
+			panicked = false
+			return v, err
+		})()
+
+		if env.reply != nil {
+			env.reply.settle(v, err)
+		}
+
+		if !panicked {
+			continue
+		}
+
+		restarts++
+		if a.maxRestarts > 0 && restarts > a.maxRestarts {
+			a.err = err
+			a.StopNow()
+			continue
+		}
+
+		handle = a.newHandler()
+	}
+}
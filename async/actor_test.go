@@ -0,0 +1,185 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+)
+
+func newCounter(opts ...async.ActorOption) *async.Actor[int, int] {
+	return async.NewActor(func() func(ctx context.Context, delta int) (int, error) {
+		total := 0
+		return func(ctx context.Context, delta int) (int, error) {
+			if delta < 0 {
+				panic("negative delta")
+			}
+			total += delta
+			return total, nil
+		}
+	}, opts...)
+}
+
+func TestActor(t *testing.T) {
+	t.Run("processes messages one at a time", func(t *testing.T) {
+		counter := newCounter(async.WithMailboxSize(10))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, counter.Tell(1))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 101, counter.Ask(1).Get())
+		require.NoError(t, counter.Stop(context.Background()))
+	})
+
+	t.Run("ask returns the handler error", func(t *testing.T) {
+		fail := errors.New("invalid")
+		a := async.NewActor(func() func(ctx context.Context, msg string) (string, error) {
+			return func(ctx context.Context, msg string) (string, error) {
+				return "", fail
+			}
+		})
+		defer a.StopNow()
+
+		_, err := a.Ask("hello").Result()
+		assert.ErrorIs(t, err, fail)
+	})
+
+	t.Run("restart on panic", func(t *testing.T) {
+		counter := newCounter()
+		defer counter.StopNow()
+
+		assert.Equal(t, 5, counter.Ask(5).Get())
+
+		var panicErr *async.PanicError
+		_, err := counter.Ask(-1).Result()
+		require.ErrorAs(t, err, &panicErr)
+
+		assert.Equal(t, 1, counter.Ask(1).Get(), "a restart must start from fresh state")
+	})
+
+	t.Run("max restarts", func(t *testing.T) {
+		counter := newCounter(async.WithMaxRestarts(1))
+
+		_, err := counter.Ask(-1).Result()
+		require.Error(t, err)
+		assert.Equal(t, 1, counter.Ask(1).Get())
+
+		_, err = counter.Ask(-1).Result()
+		require.Error(t, err)
+
+		<-counter.Done()
+		var panicErr *async.PanicError
+		assert.ErrorAs(t, counter.Err(), &panicErr)
+
+		_, err = counter.Ask(1).Result()
+		assert.ErrorIs(t, err, async.ErrActorStopped)
+	})
+
+	t.Run("stop drains the mailbox", func(t *testing.T) {
+		release := make(chan struct{})
+		a := async.NewActor(func() func(ctx context.Context, msg int) (int, error) {
+			return func(ctx context.Context, msg int) (int, error) {
+				<-release
+				return msg, nil
+			}
+		}, async.WithMailboxSize(3))
+
+		futures := []*async.Future[int]{a.Ask(1), a.Ask(2), a.Ask(3)}
+
+		stopped := make(chan error)
+		go func() { stopped <- a.Stop(context.Background()) }()
+
+		assert.Eventually(t, func() bool {
+			return errors.Is(a.Tell(4), async.ErrActorStopped)
+		}, time.Second, time.Millisecond, "a stopping actor must not accept messages")
+
+		close(release)
+		require.NoError(t, <-stopped)
+		for i, f := range futures {
+			assert.Equal(t, i+1, f.Get())
+		}
+		assert.NoError(t, a.Err())
+	})
+
+	t.Run("stop timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		a := async.NewActor(func() func(ctx context.Context, msg int) (int, error) {
+			return func(ctx context.Context, msg int) (int, error) {
+				<-release
+				return msg, nil
+			}
+		})
+		a.Ask(1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+		assert.ErrorIs(t, a.Stop(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("stop timeout with a blocked sender", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		a := async.NewActor(func() func(ctx context.Context, msg int) (int, error) {
+			return func(ctx context.Context, msg int) (int, error) {
+				close(started)
+				<-release
+				return msg, nil
+			}
+		})
+		a.Ask(1)
+		<-started
+
+		blocked := make(chan error)
+		go func() { blocked <- a.Tell(2) }()
+		time.Sleep(time.Millisecond * 10) // let the Tell block on the busy actor
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancel()
+
+		start := time.Now()
+		assert.ErrorIs(t, a.Stop(ctx), context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Millisecond*500, "Stop must respect its deadline")
+
+		assert.ErrorIs(t, <-blocked, async.ErrActorStopped)
+		assert.ErrorIs(t, a.Tell(3), async.ErrActorStopped)
+	})
+
+	t.Run("stop now", func(t *testing.T) {
+		started := make(chan struct{})
+		a := async.NewActor(func() func(ctx context.Context, msg int) (int, error) {
+			return func(ctx context.Context, msg int) (int, error) {
+				close(started)
+				<-ctx.Done()
+				return 0, ctx.Err()
+			}
+		}, async.WithMailboxSize(1))
+
+		running := a.Ask(1)
+		<-started
+		queued := a.Ask(2)
+
+		a.StopNow()
+		<-a.Done()
+
+		_, err := running.Result()
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = queued.Result()
+		assert.ErrorIs(t, err, async.ErrActorStopped)
+		assert.ErrorIs(t, a.Tell(3), async.ErrActorStopped)
+	})
+}
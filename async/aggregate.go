@@ -0,0 +1,150 @@
+package async
+
+import (
+	"context"
+
+	"github.com/lif0/pkg/errx"
+)
+
+// Settled is the outcome of a single future, as reported by AllSettled.
+type Settled[T any] struct {
+	Value T
+	Err   error
+}
+
+// All waits for all futures and returns their values in the order of the arguments.
+// It returns early with the error of the first future that fails, or with ctx.Err()
+// if ctx is done first; in both cases the values are nil.
+//
+// Example usage:
+//
+//	users, err := async.All(ctx, futures...)
+func All[T any, F Awaitable[T]](ctx context.Context, futures ...F) ([]T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	values := make([]T, len(futures))
+	results := watch[T](ctx, futures)
+
+	for range futures {
+		select {
+		case r := <-results:
+			if r.err != nil {
+				return nil, r.err
+			}
+			values[r.index] = r.value
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return values, nil
+}
+
+// Any returns the value of the first future that succeeds.
+// If all futures fail, it returns an errx.MultiError with their errors in the order of the arguments.
+// It returns ctx.Err() if ctx is done first, and ErrNoFutures if called without futures.
+func Any[T any, F Awaitable[T]](ctx context.Context, futures ...F) (T, error) {
+	var zero T
+	if len(futures) == 0 {
+		return zero, ErrNoFutures
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(futures))
+	results := watch[T](ctx, futures)
+
+	for range futures {
+		select {
+		case r := <-results:
+			if r.err == nil {
+				return r.value, nil
+			}
+			errs[r.index] = r.err
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+
+	return zero, errx.MultiError(errs)
+}
+
+// Race returns the value and the error of the first future that settles, whether it succeeds or fails.
+// It returns ctx.Err() if ctx is done first, and ErrNoFutures if called without futures.
+func Race[T any, F Awaitable[T]](ctx context.Context, futures ...F) (T, error) {
+	var zero T
+	if len(futures) == 0 {
+		return zero, ErrNoFutures
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	select {
+	case r := <-watch[T](ctx, futures):
+		return r.value, r.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// AllSettled waits for all futures and returns their outcomes in the order of the arguments.
+// The returned error is an errx.MultiError with the errors of the failed futures,
+// or nil if all of them succeeded.
+// If ctx is done first, it returns the outcomes settled so far and ctx.Err();
+// outcomes of the futures that have not settled are left zero.
+func AllSettled[T any, F Awaitable[T]](ctx context.Context, futures ...F) ([]Settled[T], error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	settled := make([]Settled[T], len(futures))
+	results := watch[T](ctx, futures)
+
+	for range futures {
+		select {
+		case r := <-results:
+			settled[r.index] = Settled[T]{Value: r.value, Err: r.err}
+		case <-ctx.Done():
+			return settled, ctx.Err()
+		}
+	}
+
+	var errs errx.MultiError
+	for _, s := range settled {
+		errs.Append(s.Err)
+	}
+
+	if errs.IsEmpty() {
+		return settled, nil
+	}
+
+	return settled, errs
+}
+
+type indexedResult[T any] struct {
+	index int
+	value T
+	err   error
+}
+
+// watch reports the result of every future on the returned channel as it settles.
+// The channel is buffered for all futures, so watchers never block on send;
+// they stop waiting once ctx is done.
+func watch[T any, F Awaitable[T]](ctx context.Context, futures []F) <-chan indexedResult[T] {
+	results := make(chan indexedResult[T], len(futures))
+
+	for i, f := range futures {
+		go func() {
+			select {
+			case <-f.Done():
+				v, err := f.Result()
+				results <- indexedResult[T]{index: i, value: v, err: err}
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	return results
+}
@@ -0,0 +1,155 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+	"github.com/lif0/pkg/errx"
+)
+
+func resolved[T any](v T) *async.Future[T] {
+	p := async.NewPromise[T]()
+	p.Resolve(v)
+	return p.GetFuture()
+}
+
+func rejected[T any](err error) *async.Future[T] {
+	p := async.NewPromise[T]()
+	p.Reject(err)
+	return p.GetFuture()
+}
+
+func pending[T any]() *async.Future[T] {
+	p := async.NewPromise[T]()
+	return p.GetFuture()
+}
+
+func TestAll(t *testing.T) {
+	t.Run("values in order", func(t *testing.T) {
+		futures := []*async.FutureAction[int]{
+			async.NewFutureAction(func() int { time.Sleep(time.Millisecond * 20); return 1 }),
+			async.NewFutureAction(func() int { return 2 }),
+			async.NewFutureAction(func() int { time.Sleep(time.Millisecond * 10); return 3 }),
+		}
+
+		values, err := async.All(context.Background(), futures...)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, values)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		values, err := async.All[int, *async.Future[int]](context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, values)
+	})
+
+	t.Run("first failure returns early", func(t *testing.T) {
+		expectedErr := errors.New("failed")
+
+		values, err := async.All(context.Background(), pending[int](), rejected[int](expectedErr))
+		assert.ErrorIs(t, err, expectedErr)
+		assert.Nil(t, values)
+	})
+
+	t.Run("ctx canceled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+
+		_, err := async.All(ctx, resolved(1), pending[int]())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestAny(t *testing.T) {
+	t.Run("first success", func(t *testing.T) {
+		v, err := async.Any(context.Background(), rejected[string](errors.New("failed")), pending[string](), resolved("ok"))
+		require.NoError(t, err)
+		assert.Equal(t, "ok", v)
+	})
+
+	t.Run("all failed", func(t *testing.T) {
+		err1 := errors.New("first")
+		err2 := errors.New("second")
+
+		_, err := async.Any(context.Background(), rejected[int](err1), rejected[int](err2))
+
+		var multiErr errx.MultiError
+		require.ErrorAs(t, err, &multiErr)
+		assert.Equal(t, errx.MultiError{err1, err2}, multiErr)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := async.Any[int, *async.Future[int]](context.Background())
+		assert.ErrorIs(t, err, async.ErrNoFutures)
+	})
+
+	t.Run("ctx canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := async.Any(ctx, pending[int]())
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestRace(t *testing.T) {
+	t.Run("first settled value", func(t *testing.T) {
+		v, err := async.Race(context.Background(), pending[int](), resolved(7))
+		require.NoError(t, err)
+		assert.Equal(t, 7, v)
+	})
+
+	t.Run("first settled error", func(t *testing.T) {
+		expectedErr := errors.New("failed")
+
+		_, err := async.Race(context.Background(), pending[int](), rejected[int](expectedErr))
+		assert.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := async.Race[int, *async.Future[int]](context.Background())
+		assert.ErrorIs(t, err, async.ErrNoFutures)
+	})
+
+	t.Run("ctx canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := async.Race(ctx, pending[int]())
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestAllSettled(t *testing.T) {
+	t.Run("mixed", func(t *testing.T) {
+		expectedErr := errors.New("failed")
+
+		settled, err := async.AllSettled(context.Background(), resolved(1), rejected[int](expectedErr), resolved(3))
+
+		var multiErr errx.MultiError
+		require.ErrorAs(t, err, &multiErr)
+		assert.Equal(t, errx.MultiError{expectedErr}, multiErr)
+		assert.Equal(t, []async.Settled[int]{{Value: 1}, {Err: expectedErr}, {Value: 3}}, settled)
+	})
+
+	t.Run("all succeeded", func(t *testing.T) {
+		settled, err := async.AllSettled(context.Background(), resolved("a"), resolved("b"))
+		require.NoError(t, err)
+		assert.Equal(t, []async.Settled[string]{{Value: "a"}, {Value: "b"}}, settled)
+	})
+
+	t.Run("ctx canceled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+
+		settled, err := async.AllSettled(ctx, resolved(1), pending[int]())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, []async.Settled[int]{{Value: 1}, {}}, settled)
+	})
+}
@@ -0,0 +1,88 @@
+package async
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff computes the delay before the next attempt of a retried operation.
+// attempt is the number of attempts made so far (starting at 1) and prev is the delay
+// returned for the previous attempt (0 for the first one).
+type Backoff func(attempt int, prev time.Duration) time.Duration
+
+// ConstantBackoff returns a Backoff that always waits d.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int, time.Duration) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff returns a Backoff that doubles the delay on every attempt:
+// base, 2*base, 4*base, ... never exceeding maxDelay. If maxDelay <= 0, the delay is not capped.
+func ExponentialBackoff(base, maxDelay time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		d := base
+		for i := 1; i < attempt && !exceeds(d, maxDelay); i++ {
+			if d > math.MaxInt64/2 {
+				return capDelay(math.MaxInt64, maxDelay)
+			}
+			d *= 2
+		}
+
+		return capDelay(d, maxDelay)
+	}
+}
+
+// FibonacciBackoff returns a Backoff that grows the delay along the Fibonacci sequence:
+// base, base, 2*base, 3*base, 5*base, ... never exceeding maxDelay.
+// If maxDelay <= 0, the delay is not capped.
+func FibonacciBackoff(base, maxDelay time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		a, b := base, base
+		for i := 1; i < attempt && !exceeds(a, maxDelay); i++ {
+			if b > math.MaxInt64-a {
+				return capDelay(math.MaxInt64, maxDelay)
+			}
+			a, b = b, a+b
+		}
+
+		return capDelay(a, maxDelay)
+	}
+}
+
+// DecorrelatedJitterBackoff returns a Backoff that picks a random delay between base and
+// three times the previous delay, never exceeding maxDelay. It spreads the retries of many
+// clients over time better than plain exponential backoff.
+// If maxDelay <= 0, the delay is not capped.
+//
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/ for details.
+func DecorrelatedJitterBackoff(base, maxDelay time.Duration) Backoff {
+	return func(_ int, prev time.Duration) time.Duration {
+		upper := max(prev, base)
+		if upper > math.MaxInt64/3 {
+			upper = math.MaxInt64
+		} else {
+			upper *= 3
+		}
+
+		if upper <= base {
+			return capDelay(base, maxDelay)
+		}
+
+		d := base + time.Duration(rand.Int64N(int64(upper-base))) // #nosec G404 -- jitter does not need a cryptographic source
+		return capDelay(d, maxDelay)
+	}
+}
+
+func exceeds(d, maxDelay time.Duration) bool {
+	return maxDelay > 0 && d >= maxDelay
+}
+
+func capDelay(d, maxDelay time.Duration) time.Duration {
+	if exceeds(d, maxDelay) {
+		return maxDelay
+	}
+
+	return d
+}
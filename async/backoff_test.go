@@ -0,0 +1,72 @@
+package async_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lif0/pkg/async"
+)
+
+func delays(b async.Backoff, n int) []time.Duration {
+	out := make([]time.Duration, 0, n)
+
+	var prev time.Duration
+	for attempt := 1; attempt <= n; attempt++ {
+		prev = b(attempt, prev)
+		out = append(out, prev)
+	}
+
+	return out
+}
+
+func TestConstantBackoff(t *testing.T) {
+	assert.Equal(t, []time.Duration{5, 5, 5}, delays(async.ConstantBackoff(5), 3))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Run("capped", func(t *testing.T) {
+		assert.Equal(t, []time.Duration{1, 2, 4, 8, 10, 10}, delays(async.ExponentialBackoff(1, 10), 6))
+	})
+
+	t.Run("uncapped", func(t *testing.T) {
+		assert.Equal(t, []time.Duration{3, 6, 12, 24}, delays(async.ExponentialBackoff(3, 0), 4))
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		assert.Equal(t, time.Duration(math.MaxInt64), async.ExponentialBackoff(time.Second, 0)(200, 0))
+	})
+}
+
+func TestFibonacciBackoff(t *testing.T) {
+	t.Run("capped", func(t *testing.T) {
+		assert.Equal(t, []time.Duration{2, 2, 4, 6, 10, 12, 12}, delays(async.FibonacciBackoff(2, 12), 7))
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		assert.Equal(t, time.Duration(math.MaxInt64), async.FibonacciBackoff(time.Second, 0)(500, 0))
+	})
+}
+
+func TestDecorrelatedJitterBackoff(t *testing.T) {
+	const (
+		base     = time.Millisecond
+		maxDelay = time.Second
+	)
+
+	b := async.DecorrelatedJitterBackoff(base, maxDelay)
+
+	var prev time.Duration
+	for attempt := 1; attempt <= 100; attempt++ {
+		d := b(attempt, prev)
+		assert.GreaterOrEqual(t, d, base)
+		assert.LessOrEqual(t, d, maxDelay)
+		assert.LessOrEqual(t, d, max(prev, base)*3)
+		prev = d
+	}
+
+	assert.Equal(t, time.Duration(0), async.DecorrelatedJitterBackoff(0, 0)(1, 0))
+	assert.LessOrEqual(t, async.DecorrelatedJitterBackoff(1, 0)(1, math.MaxInt64), time.Duration(math.MaxInt64))
+}
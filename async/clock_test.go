@@ -0,0 +1,28 @@
+package async_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lif0/pkg/async"
+)
+
+func TestSystemClock(t *testing.T) {
+	clock := async.SystemClock()
+
+	before := time.Now()
+	assert.False(t, clock.Now().Before(before))
+
+	timer := clock.NewTimer(time.Millisecond)
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+	assert.False(t, timer.Stop())
+
+	assert.False(t, timer.Reset(time.Hour))
+	assert.True(t, timer.Stop())
+}
@@ -0,0 +1,113 @@
+package async
+
+// Then returns a Future settled with fn applied to the value of f.
+// If f settles with an error, fn is not called and the returned Future is settled with that error.
+//
+// fn runs in a goroutine started by Then once f is settled. A panic in fn is recovered
+// and settles the returned Future with a *PanicError.
+//
+// Example usage:
+//
+//	length := async.Then(future, func(s string) int { return len(s) })
+//	fmt.Println(length.Get())
+func Then[T, U any](f Awaitable[T], fn func(T) U) *Future[U] {
+	return chain(f, func(v T, err error) (U, error) {
+		if err != nil {
+			var zero U
+			return zero, err
+		}
+
+		return fn(v), nil
+	})
+}
+
+// Map returns a Future settled with the result of fn applied to the value of f.
+// Unlike Then, fn may fail: its error settles the returned Future.
+// If f settles with an error, fn is not called and the returned Future is settled with that error.
+//
+// fn runs in a goroutine started by Map once f is settled. A panic in fn is recovered
+// and settles the returned Future with a *PanicError.
+func Map[T, U any](f Awaitable[T], fn func(T) (U, error)) *Future[U] {
+	return chain(f, func(v T, err error) (U, error) {
+		if err != nil {
+			var zero U
+			return zero, err
+		}
+
+		return fn(v)
+	})
+}
+
+// FlatMap returns a Future settled with the result of the Future returned by fn,
+// which is applied to the value of f. It chains asynchronous steps without nesting.
+// If f settles with an error, fn is not called and the returned Future is settled with that error.
+// If fn returns nil, the returned Future is settled with the zero value.
+//
+// fn runs in a goroutine started by FlatMap once f is settled. A panic in fn is recovered
+// and settles the returned Future with a *PanicError.
+//
+// Example usage:
+//
+//	profile := async.FlatMap(userFuture, func(u User) *async.Future[Profile] {
+//		return async.NewFutureActionErr(func() (Profile, error) {
+//			return loadProfile(u.ID)
+//		}).Future
+//	})
+func FlatMap[T, U any](f Awaitable[T], fn func(T) *Future[U]) *Future[U] {
+	return chain(f, func(v T, err error) (U, error) {
+		if err != nil {
+			var zero U
+			return zero, err
+		}
+
+		next := fn(v)
+		if next == nil {
+			var zero U
+			return zero, nil
+		}
+
+		return next.Result()
+	})
+}
+
+// Catch returns a Future that handles an error of f with fn.
+// If f settles with an error, the returned Future is settled with the result of fn;
+// fn may return a fallback value with a nil error, or a new error.
+// If f settles without an error, fn is not called and the value of f is passed through.
+//
+// fn runs in a goroutine started by Catch once f is settled. A panic in fn is recovered
+// and settles the returned Future with a *PanicError.
+func Catch[T any](f Awaitable[T], fn func(error) (T, error)) *Future[T] {
+	return chain(f, func(v T, err error) (T, error) {
+		if err == nil {
+			return v, nil
+		}
+
+		return fn(err)
+	})
+}
+
+// Recover returns a Future that replaces an error of f with the fallback value returned by fn.
+// If f settles without an error, fn is not called and the value of f is passed through.
+//
+// fn runs in a goroutine started by Recover once f is settled. A panic in fn is recovered
+// and settles the returned Future with a *PanicError.
+func Recover[T any](f Awaitable[T], fn func(error) T) *Future[T] {
+	return Catch(f, func(err error) (T, error) {
+		return fn(err), nil
+	})
+}
+
+// chain settles a new Future with fn applied to the result of f, once f is settled.
+func chain[T, U any](f Awaitable[T], fn func(T, error) (U, error)) *Future[U] {
+	s := newState[U]()
+
+	go func() {
+		v, err := f.Result()
+		s.settle(withRecover(func() (U, error) {
+			return fn(v, err)
+		})())
+	}()
+
+	return &Future[U]{state: s}
+}
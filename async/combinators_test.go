@@ -0,0 +1,160 @@
+package async_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lif0/pkg/async"
+)
+
+func TestThen(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		future := async.NewFutureAction(func() string { return "hello" })
+
+		v, err := async.Then(future, func(s string) int { return len(s) }).Result()
+		assert.NoError(t, err)
+		assert.Equal(t, 5, v)
+	})
+
+	t.Run("error skips fn", func(t *testing.T) {
+		expectedErr := errors.New("failed")
+		p := async.NewPromise[string]()
+		p.Reject(expectedErr)
+
+		called := false
+		v, err := async.Then(p.GetFuture(), func(s string) int {
+			called = true
+			return len(s)
+		}).Result()
+		assert.ErrorIs(t, err, expectedErr)
+		assert.Zero(t, v)
+		assert.False(t, called)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		future := async.NewFutureAction(func() int { return 0 })
+
+		_, err := async.Then(future, func(int) int { panic("boom") }).Result()
+
+		var panicErr *async.PanicError
+		assert.ErrorAs(t, err, &panicErr)
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		future := async.NewFutureAction(func() int { return 1 })
+
+		inc := func(v int) int { return v + 1 }
+		v := async.Then(async.Then(async.Then(future, inc), inc), inc).Get()
+		assert.Equal(t, 4, v)
+	})
+}
+
+func TestMap(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		future := async.NewFutureAction(func() string { return "42" })
+
+		v, err := async.Map(future, strconv.Atoi).Result()
+		assert.NoError(t, err)
+		assert.Equal(t, 42, v)
+	})
+
+	t.Run("fn error", func(t *testing.T) {
+		future := async.NewFutureAction(func() string { return "forty-two" })
+
+		_, err := async.Map(future, strconv.Atoi).Result()
+		assert.ErrorIs(t, err, strconv.ErrSyntax)
+	})
+
+	t.Run("source error", func(t *testing.T) {
+		expectedErr := errors.New("failed")
+		future := async.NewFutureActionErr(func() (string, error) { return "", expectedErr })
+
+		_, err := async.Map(future, strconv.Atoi).Result()
+		assert.ErrorIs(t, err, expectedErr)
+	})
+}
+
+func TestFlatMap(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		future := async.NewFutureAction(func() int { return 20 })
+
+		v, err := async.FlatMap(future, func(v int) *async.Future[int] {
+			return async.NewFutureAction(func() int { return v * 2 }).Future
+		}).Result()
+		assert.NoError(t, err)
+		assert.Equal(t, 40, v)
+	})
+
+	t.Run("inner error", func(t *testing.T) {
+		expectedErr := errors.New("inner")
+		future := async.NewFutureAction(func() int { return 1 })
+
+		_, err := async.FlatMap(future, func(int) *async.Future[int] {
+			p := async.NewPromise[int]()
+			p.Reject(expectedErr)
+			return p.GetFuture()
+		}).Result()
+		assert.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("source error", func(t *testing.T) {
+		expectedErr := errors.New("outer")
+		future := async.NewFutureActionErr(func() (int, error) { return 0, expectedErr })
+
+		_, err := async.FlatMap(future, func(int) *async.Future[int] {
+			t.Error("fn must not be called")
+			return nil
+		}).Result()
+		assert.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("nil future", func(t *testing.T) {
+		future := async.NewFutureAction(func() int { return 1 })
+
+		v, err := async.FlatMap(future, func(int) *async.Future[string] { return nil }).Result()
+		assert.NoError(t, err)
+		assert.Empty(t, v)
+	})
+}
+
+func TestCatch(t *testing.T) {
+	t.Run("fallback", func(t *testing.T) {
+		future := async.NewFutureActionErr(func() (string, error) { return "", errors.New("failed") })
+
+		v, err := async.Catch(future, func(error) (string, error) { return "fallback", nil }).Result()
+		assert.NoError(t, err)
+		assert.Equal(t, "fallback", v)
+	})
+
+	t.Run("rewrap", func(t *testing.T) {
+		cause := errors.New("failed")
+		future := async.NewFutureActionErr(func() (string, error) { return "", cause })
+
+		_, err := async.Catch(future, func(err error) (string, error) {
+			return "", errors.Join(errors.New("load config"), err)
+		}).Result()
+		assert.ErrorIs(t, err, cause)
+	})
+
+	t.Run("value passes through", func(t *testing.T) {
+		future := async.NewFutureAction(func() string { return "value" })
+
+		v, err := async.Catch(future, func(error) (string, error) {
+			t.Error("fn must not be called")
+			return "", nil
+		}).Result()
+		assert.NoError(t, err)
+		assert.Equal(t, "value", v)
+	})
+}
+
+func TestRecover(t *testing.T) {
+	future := async.NewFutureAction(func() int { panic("boom") }, async.WithPanicRecovery())
+
+	v, err := async.Recover(future, func(error) int { return -1 }).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, -1, v)
+}
@@ -0,0 +1,284 @@
+package async
+
+import (
+	"sync"
+	"time"
+)
+
+// DebounceOption configures Debounce and Throttle. Besides the options below, it can be a ClockOption.
+type DebounceOption interface {
+	applyDebounce(o *debounceOptions)
+}
+
+type debounceOptionFunc func(*debounceOptions)
+
+func (f debounceOptionFunc) applyDebounce(o *debounceOptions) { f(o) }
+
+type debounceOptions struct {
+	clock    Clock
+	leading  bool
+	trailing bool
+	maxWait  time.Duration
+}
+
+func newDebounceOptions(o debounceOptions, opts []DebounceOption) debounceOptions {
+	o.clock = SystemClock()
+	for _, opt := range opts {
+		opt.applyDebounce(&o)
+	}
+
+	return o
+}
+
+func (c ClockOption) applyDebounce(o *debounceOptions) {
+	if c.clock != nil {
+		o.clock = c.clock
+	}
+}
+
+// WithLeading sets whether Debounce and Throttle invoke the function on the leading edge,
+// that is on the first call of a burst. The default is false for Debounce and true for Throttle.
+func WithLeading(enabled bool) DebounceOption {
+	return debounceOptionFunc(func(o *debounceOptions) {
+		o.leading = enabled
+	})
+}
+
+// WithTrailing sets whether Debounce and Throttle invoke the function on the trailing edge,
+// that is once the wait is over, if there were calls since the last invocation. The default is true.
+func WithTrailing(enabled bool) DebounceOption {
+	return debounceOptionFunc(func(o *debounceOptions) {
+		o.trailing = enabled
+	})
+}
+
+// WithMaxWait bounds how long Debounce may delay an invocation while calls keep coming:
+// the function is invoked at most maxWait after the first call of a burst.
+// The default is 0, no bound.
+func WithMaxWait(maxWait time.Duration) DebounceOption {
+	return debounceOptionFunc(func(o *debounceOptions) {
+		o.maxWait = max(maxWait, 0)
+	})
+}
+
+// Debouncer coalesces bursts of calls into fewer invocations of a function.
+// It is created by Debounce; Throttler is built on it.
+//
+// Every Call returns the Future of the invocation that serves it, settled with the result of
+// the function. A call that no invocation serves, because it falls on a disabled edge or is
+// canceled, gets a Future settled with ErrDropped. A panic in the function is recovered and
+// returned as a *PanicError. The function runs in its own goroutine.
+//
+// All methods are safe for concurrent use by multiple goroutines.
+type Debouncer[T any] struct {
+	fn       func() (T, error)
+	wait     time.Duration
+	maxWait  time.Duration
+	leading  bool
+	trailing bool
+	clock    Clock
+
+	mu       sync.Mutex
+	stop     chan struct{} // closed by Cancel; nil while no wait is in progress
+	start    time.Time     // start of the current wait, the base of maxWait
+	deadline time.Time     // end of the current wait
+	pending  *state[T]     // the trailing invocation, if any call is waiting for it
+}
+
+// Debounce returns a Debouncer that invokes fn once calls have stopped coming for wait.
+// By default fn is invoked on the trailing edge only: a burst of calls results in one invocation,
+// wait after the last call. It accepts WithLeading, WithTrailing, WithMaxWait and WithClock.
+//
+// Example usage:
+//
+//	reload := async.Debounce(func() (*Config, error) {
+//		return loadConfig("config.yaml")
+//	}, time.Second)
+//
+//	for range fileChanged {
+//		reload.Call() // a burst of writes reloads the file once
+//	}
+func Debounce[T any](fn func() (T, error), wait time.Duration, opts ...DebounceOption) *Debouncer[T] {
+	o := newDebounceOptions(debounceOptions{trailing: true}, opts)
+	return newDebouncer(fn, wait, o)
+}
+
+func newDebouncer[T any](fn func() (T, error), wait time.Duration, o debounceOptions) *Debouncer[T] {
+	return &Debouncer[T]{
+		fn:       fn,
+		wait:     wait,
+		maxWait:  o.maxWait,
+		leading:  o.leading,
+		trailing: o.trailing,
+		clock:    o.clock,
+	}
+}
+
+// Call schedules an invocation of the function and returns the Future of the invocation that serves the call.
+func (d *Debouncer[T]) Call() *Future[T] {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.clock.Now()
+
+	if d.stop == nil {
+		d.begin(now)
+		if d.leading {
+			s := newState[T]()
+			d.invoke(s)
+			return &Future[T]{state: s}
+		}
+	} else {
+		d.extend(now)
+	}
+
+	if !d.trailing {
+		return dropped[T]()
+	}
+
+	if d.pending == nil {
+		d.pending = newState[T]()
+	}
+
+	return &Future[T]{state: d.pending}
+}
+
+// Flush invokes the pending trailing invocation now instead of at the end of the wait.
+// It reports whether there was one.
+func (d *Debouncer[T]) Flush() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pending == nil {
+		return false
+	}
+
+	d.invoke(d.pending)
+	d.pending = nil
+
+	return true
+}
+
+// Cancel drops the pending trailing invocation, settling its Future with ErrDropped,
+// and ends the current wait, so that the next call starts a new burst.
+func (d *Debouncer[T]) Cancel() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop == nil {
+		return
+	}
+
+	close(d.stop)
+	d.stop = nil
+
+	if d.pending != nil {
+		var zero T
+		d.pending.settle(zero, ErrDropped)
+		d.pending = nil
+	}
+}
+
+// begin starts a new wait at now. d.mu must be held.
+func (d *Debouncer[T]) begin(now time.Time) {
+	d.stop = make(chan struct{})
+	d.start = now
+	d.deadline = now.Add(d.wait)
+
+	go d.run(d.clock.NewTimer(d.wait), d.stop)
+}
+
+// extend moves the end of the current wait to wait after now, but no later than maxWait after its start.
+// d.mu must be held.
+func (d *Debouncer[T]) extend(now time.Time) {
+	d.deadline = now.Add(d.wait)
+	if d.maxWait > 0 && d.deadline.Sub(d.start) > d.maxWait {
+		d.deadline = d.start.Add(d.maxWait)
+	}
+}
+
+// run waits for the end of the wait started with stop, invoking the trailing invocation
+// and starting a new wait as long as calls keep coming.
+func (d *Debouncer[T]) run(timer Timer, stop chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+
+		d.mu.Lock()
+
+		if d.stop != stop {
+			d.mu.Unlock()
+			return
+		}
+
+		now := d.clock.Now()
+		if now.Before(d.deadline) {
+			// The wait was extended by later calls.
+			timer.Reset(d.deadline.Sub(now))
+			d.mu.Unlock()
+			continue
+		}
+
+		if d.pending == nil {
+			d.stop = nil
+			d.mu.Unlock()
+			return
+		}
+
+		// Keep waiting after a trailing invocation, so that a call right after it
+		// does not cause a leading invocation too.
+		d.invoke(d.pending)
+		d.pending = nil
+		d.start = now
+		d.deadline = now.Add(d.wait)
+		timer.Reset(d.wait)
+
+		d.mu.Unlock()
+	}
+}
+
+// invoke calls the function in a new goroutine and settles s with its result.
+func (d *Debouncer[T]) invoke(s *state[T]) {
+	go func() {
+		v, err := withRecover(d.fn)()
+		s.settle(v, err)
+	}()
+}
+
+// Throttler limits the invocations of a function to one per interval.
+// It is created by Throttle and has the methods of Debouncer.
+type Throttler[T any] struct {
+	*Debouncer[T]
+}
+
+// Throttle returns a Throttler that invokes fn at most once per interval.
+// By default the first call of a burst invokes fn at once (leading edge), and the calls made
+// during the interval are served by one more invocation at its end (trailing edge).
+// It accepts WithLeading, WithTrailing and WithClock.
+//
+// Example usage:
+//
+//	flush := async.Throttle(func() (int, error) {
+//		return metrics.Flush()
+//	}, 10*time.Second)
+//
+//	for range events {
+//		flush.Call() // metrics are flushed at most every 10 seconds
+//	}
+func Throttle[T any](fn func() (T, error), interval time.Duration, opts ...DebounceOption) *Throttler[T] {
+	o := newDebounceOptions(debounceOptions{leading: true, trailing: true}, opts)
+	o.maxWait = interval
+
+	return &Throttler[T]{Debouncer: newDebouncer(fn, interval, o)}
+}
+
+func dropped[T any]() *Future[T] {
+	s := newState[T]()
+	var zero T
+	s.settle(zero, ErrDropped)
+	return &Future[T]{state: s}
+}
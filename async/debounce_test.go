@@ -0,0 +1,154 @@
+package async_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+)
+
+func counter() (func() (int, error), *atomic.Int32) {
+	var calls atomic.Int32
+	return func() (int, error) {
+		return int(calls.Add(1)), nil
+	}, &calls
+}
+
+func TestDebounce(t *testing.T) {
+	t.Run("trailing", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		fn, calls := counter()
+		d := async.Debounce(fn, time.Millisecond*100, async.WithClock(clock))
+
+		first := d.Call()
+		clock.Advance(time.Millisecond * 50)
+		second := d.Call()
+
+		clock.Advance(time.Millisecond * 50)
+		clock.WaitForTimers(1)
+		_, ok := first.TryGet()
+		assert.False(t, ok, "a call must push the invocation back")
+
+		clock.Advance(time.Millisecond * 50)
+		assert.Equal(t, 1, first.Get())
+		assert.Equal(t, 1, second.Get())
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("leading", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		fn, calls := counter()
+		d := async.Debounce(fn, time.Millisecond*100, async.WithClock(clock), async.WithLeading(true), async.WithTrailing(false))
+
+		assert.Equal(t, 1, d.Call().Get())
+
+		_, err := d.Call().Result()
+		assert.ErrorIs(t, err, async.ErrDropped)
+
+		clock.Advance(time.Millisecond * 100)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("max wait", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		fn, _ := counter()
+		d := async.Debounce(fn, time.Millisecond*100, async.WithClock(clock), async.WithMaxWait(time.Millisecond*250))
+
+		first := d.Call()
+		for range 4 {
+			clock.Advance(time.Millisecond * 50)
+			clock.WaitForTimers(1)
+			d.Call()
+		}
+
+		_, ok := first.TryGet()
+		assert.False(t, ok)
+
+		clock.Advance(time.Millisecond * 50)
+		assert.Equal(t, 1, first.Get(), "calls must not delay the invocation past the max wait")
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		fn, calls := counter()
+		d := async.Debounce(fn, time.Minute, async.WithClock(clock))
+
+		assert.False(t, d.Flush())
+
+		future := d.Call()
+		assert.True(t, d.Flush())
+		assert.Equal(t, 1, future.Get())
+
+		assert.False(t, d.Flush())
+		clock.Advance(time.Minute)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		fn, calls := counter()
+		d := async.Debounce(fn, time.Minute, async.WithClock(clock))
+
+		future := d.Call()
+		d.Cancel()
+
+		_, err := future.Result()
+		assert.ErrorIs(t, err, async.ErrDropped)
+
+		clock.Advance(time.Hour)
+		assert.Equal(t, int32(0), calls.Load())
+
+		next := d.Call()
+		clock.Advance(time.Minute)
+		assert.Equal(t, 1, next.Get())
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		d := async.Debounce(func() (int, error) { panic("boom") }, time.Millisecond)
+
+		var panicErr *async.PanicError
+		_, err := d.Call().Result()
+		assert.ErrorAs(t, err, &panicErr)
+	})
+
+	t.Run("system clock", func(t *testing.T) {
+		fn, _ := counter()
+		d := async.Debounce(fn, time.Millisecond)
+
+		v, err := d.Call().Result()
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+	})
+}
+
+func TestThrottle(t *testing.T) {
+	clock := async.NewFakeClock(epoch)
+	fn, calls := counter()
+	th := async.Throttle(fn, time.Millisecond*100, async.WithClock(clock))
+
+	assert.Equal(t, 1, th.Call().Get(), "the first call must invoke at once")
+
+	second, third := th.Call(), th.Call()
+	clock.Advance(time.Millisecond * 50)
+	th.Call()
+	assert.Equal(t, int32(1), calls.Load(), "calls must not extend the interval")
+
+	clock.Advance(time.Millisecond * 50)
+	assert.Equal(t, 2, second.Get())
+	assert.Equal(t, 2, third.Get())
+
+	clock.WaitForTimers(1)
+	fourth := th.Call()
+	assert.Equal(t, int32(2), calls.Load(), "a call right after the trailing invocation must wait for the interval")
+
+	clock.Advance(time.Millisecond * 100)
+	assert.Equal(t, 3, fourth.Get())
+
+	clock.WaitForTimers(1)
+	clock.Advance(time.Millisecond * 100)
+	assert.Equal(t, 4, th.Call().Get())
+}
@@ -0,0 +1,75 @@
+package async
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+var (
+	// ErrTimeout reports that a future was not resolved within the given timeout.
+	ErrTimeout = errors.New("future timed out")
+
+	// ErrNoFutures reports that Any or Race was called without futures.
+	ErrNoFutures = errors.New("no futures to wait for")
+
+	// ErrRejected reports that an Executor rejected a task because its queue was full.
+	ErrRejected = errors.New("task rejected: executor queue is full")
+
+	// ErrExecutorShutdown reports that a task was submitted to, or dropped by, an Executor that is shut down.
+	ErrExecutorShutdown = errors.New("executor is shut down")
+
+	// ErrInvalidGraph reports that a Graph has a dependency cycle, a duplicate node or a dependency on an unknown node.
+	ErrInvalidGraph = errors.New("invalid graph")
+
+	// ErrSkipped reports that a Graph node did not run because a dependency failed or the Graph was cancelled.
+	ErrSkipped = errors.New("node skipped")
+
+	// ErrDropped reports that a call to a Debouncer or Throttler was dropped without invoking its function,
+	// because the call fell on a disabled edge or was canceled.
+	ErrDropped = errors.New("call dropped")
+
+	// ErrActorStopped reports that a message was sent to, or dropped by, an Actor that is stopped.
+	ErrActorStopped = errors.New("actor is stopped")
+
+	// ErrRestartIntensity reports that a Supervisor gave up because its children failed too often.
+	ErrRestartIntensity = errors.New("restart intensity exceeded")
+)
+
+// PanicError is the error produced when a panic raised by an asynchronous task is recovered.
+// It keeps the recovered value and the stack trace of the panicking goroutine.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace captured at the point of recovery.
+	Stack []byte
+}
+
+// Error returns the panic value followed by the stack trace.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n\n%s", e.Value, e.Stack)
+}
+
+// Unwrap returns the panic value if it is an error, otherwise nil.
+// This allows errors.Is and errors.As to see through the panic.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// withRecover wraps fn so that a panic raised by it is returned as a *PanicError.
+func withRecover[T any](fn func() (T, error)) func() (T, error) {
+	return func() (v T, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				var zero T
+				v, err = zero, &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+
+		return fn()
+	}
+}
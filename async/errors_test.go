@@ -0,0 +1,27 @@
+package async_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lif0/pkg/async"
+)
+
+func TestPanicError(t *testing.T) {
+	t.Run("error value", func(t *testing.T) {
+		cause := errors.New("boom")
+		err := &async.PanicError{Value: cause, Stack: []byte("stack")}
+
+		assert.ErrorIs(t, err, cause)
+		assert.Equal(t, "panic: boom\n\nstack", err.Error())
+	})
+
+	t.Run("non-error value", func(t *testing.T) {
+		err := &async.PanicError{Value: 42}
+
+		assert.NoError(t, err.Unwrap())
+		assert.Contains(t, err.Error(), "panic: 42")
+	})
+}
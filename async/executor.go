@@ -0,0 +1,271 @@
+package async
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/lif0/pkg/syncx"
+)
+
+// OverflowPolicy decides what Submit does when the queue of an Executor is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes Submit wait until the queue has room. It is the default.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowReject settles the future returned by Submit with ErrRejected.
+	OverflowReject
+	// OverflowCallerRuns runs the task synchronously in the goroutine that calls Submit,
+	// holding a slot of the semaphore set by WithSemaphore like a worker does.
+	OverflowCallerRuns
+)
+
+// ExecutorOption configures an Executor.
+type ExecutorOption func(*executorOptions)
+
+type executorOptions struct {
+	queueSize int
+	policy    OverflowPolicy
+	sem       *syncx.Semaphore
+}
+
+// WithQueueSize sets the number of tasks that may wait for a free worker.
+// The default is 0: once every worker is busy, Submit applies the overflow policy.
+func WithQueueSize(size int) ExecutorOption {
+	return func(o *executorOptions) {
+		o.queueSize = max(size, 0)
+	}
+}
+
+// WithOverflowPolicy sets what Submit does when the queue is full. The default is OverflowBlock.
+func WithOverflowPolicy(policy OverflowPolicy) ExecutorOption {
+	return func(o *executorOptions) {
+		o.policy = policy
+	}
+}
+
+// WithSemaphore makes every task hold a slot of sem while it runs, including a task that
+// OverflowCallerRuns runs in the calling goroutine.
+// Sharing one semaphore between several Executors bounds their combined concurrency.
+func WithSemaphore(sem *syncx.Semaphore) ExecutorOption {
+	return func(o *executorOptions) {
+		o.sem = sem
+	}
+}
+
+// Executor runs submitted tasks on a fixed number of worker goroutines.
+// Tasks wait in a bounded queue; what happens when the queue is full is set by the OverflowPolicy.
+// Unlike NewFutureAction, which starts a goroutine per call, an Executor never runs more
+// than its number of workers at once, apart from the tasks OverflowCallerRuns runs in the callers,
+// and never more than the semaphore set by WithSemaphore allows.
+//
+// A panic in a task is recovered and settles its future with a *PanicError.
+//
+// All methods are safe for concurrent use by multiple goroutines.
+//
+// Example usage:
+//
+//	exec := async.NewExecutor(8, async.WithQueueSize(100))
+//	defer exec.Shutdown(context.Background())
+//
+//	future := async.Submit(exec, func(ctx context.Context) (Response, error) {
+//		return client.Do(ctx, req)
+//	})
+type Executor struct {
+	queue  chan task
+	slots  chan struct{} // one per task queued or running; full when every worker is busy and the queue is full
+	sem    *syncx.Semaphore
+	policy OverflowPolicy
+
+	ctx    context.Context // canceled by ShutdownNow
+	cancel context.CancelFunc
+
+	mu          sync.RWMutex // guards closed and sends to queue
+	closed      bool
+	closing     chan struct{} // closed before close takes mu, to release blocked Submit calls
+	closingOnce sync.Once
+	wg          sync.WaitGroup
+}
+
+type task struct {
+	run   func(ctx context.Context)
+	abort func(err error)
+}
+
+// NewExecutor creates an Executor with the given number of workers and starts them.
+// If workers is 0, runtime.GOMAXPROCS(0) workers are started.
+func NewExecutor(workers uint, opts ...ExecutorOption) *Executor {
+	var o executorOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if workers == 0 {
+		workers = uint(runtime.GOMAXPROCS(0)) // #nosec G115 -- GOMAXPROCS is positive
+	}
+
+	capacity := int(workers) + o.queueSize // #nosec G115 -- the number of workers fits in int
+	e := &Executor{
+		queue:   make(chan task, capacity),
+		slots:   make(chan struct{}, capacity),
+		sem:     o.sem,
+		policy:  o.policy,
+		closing: make(chan struct{}),
+	}
+	e.ctx, e.cancel = context.WithCancel(context.Background())
+
+	e.wg.Add(int(workers)) // #nosec G115 -- the number of workers fits in int
+	for range workers {
+		go e.work()
+	}
+
+	return e
+}
+
+// Submit queues fn for execution on e and returns a Future for its result.
+// fn receives a context that is canceled by ShutdownNow.
+//
+// If e is shut down, or the queue is full and the policy is OverflowReject, fn is not run
+// and the Future is settled with ErrExecutorShutdown or ErrRejected.
+// Submit is a function rather than a method because Go methods cannot have type parameters.
+func Submit[T any](e *Executor, fn func(ctx context.Context) (T, error)) *Future[T] {
+	s := newState[T]()
+
+	t := task{
+		run: func(ctx context.Context) {
+			s.settle(withRecover(func() (T, error) { return fn(ctx) })())
+		},
+		abort: func(err error) {
+			var zero T
+			s.settle(zero, err)
+		},
+	}
+
+	queued, err := e.enqueue(t)
+	switch {
+	case err != nil:
+		t.abort(err)
+	case !queued:
+		e.execute(t) // OverflowCallerRuns
+	}
+
+	return &Future[T]{state: s}
+}
+
+// enqueue puts t into the queue according to the overflow policy.
+// It reports false with a nil error if the caller must run t itself.
+func (e *Executor) enqueue(t task) (bool, error) {
+	select {
+	case <-e.closing:
+		return false, ErrExecutorShutdown // do not wait for a pending close to take the lock
+	default:
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.closed {
+		return false, ErrExecutorShutdown
+	}
+
+	// A slot is free while a worker is idle or the queue has room, so the send to the queue
+	// below never blocks: the policy only applies once every worker is busy and the queue is full.
+	switch e.policy {
+	case OverflowReject:
+		select {
+		case e.slots <- struct{}{}:
+		default:
+			return false, ErrRejected
+		}
+	case OverflowCallerRuns:
+		select {
+		case e.slots <- struct{}{}:
+		default:
+			return false, nil
+		}
+	default:
+		select {
+		case e.slots <- struct{}{}:
+		case <-e.closing:
+			return false, ErrExecutorShutdown
+		case <-e.ctx.Done():
+			return false, ErrExecutorShutdown
+		}
+	}
+
+	e.queue <- t
+	return true, nil
+}
+
+// Shutdown stops accepting new tasks and waits until the queued and running tasks are finished
+// or ctx is done, in which case it returns ctx.Err(). Submit calls blocked on a full queue
+// are released with ErrExecutorShutdown. Shutdown may be called multiple times.
+func (e *Executor) Shutdown(ctx context.Context) error {
+	e.markClosing()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.close()
+		e.wg.Wait()
+		e.cancel() // all tasks are finished, release the context
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ShutdownNow stops accepting new tasks, cancels the context of the running tasks and
+// settles the futures of the queued tasks with ErrExecutorShutdown without running them.
+// It does not wait for the running tasks to return; call Shutdown for that.
+func (e *Executor) ShutdownNow() {
+	e.cancel() // release Submit calls blocked on a full queue before taking the lock
+	e.close()
+}
+
+// markClosing makes new and blocked Submit calls fail without waiting for close to take the lock.
+func (e *Executor) markClosing() {
+	e.closingOnce.Do(func() { close(e.closing) })
+}
+
+func (e *Executor) close() {
+	e.markClosing()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.closed {
+		e.closed = true
+		close(e.queue)
+	}
+}
+
+func (e *Executor) work() {
+	defer e.wg.Done()
+
+	for t := range e.queue {
+		e.execute(t)
+		<-e.slots
+	}
+}
+
+// execute runs t while holding a slot of the semaphore, unless the executor was shut down with ShutdownNow.
+func (e *Executor) execute(t task) {
+	if e.ctx.Err() != nil {
+		t.abort(ErrExecutorShutdown)
+		return
+	}
+
+	if err := e.sem.AcquireContext(e.ctx); err != nil {
+		t.abort(ErrExecutorShutdown)
+		return
+	}
+	defer e.sem.Release()
+
+	t.run(e.ctx)
+}
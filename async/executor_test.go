@@ -0,0 +1,338 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+	"github.com/lif0/pkg/syncx"
+)
+
+// blockWorker submits a task that occupies a worker until release is closed.
+func blockWorker(t *testing.T, exec *async.Executor) (release func()) {
+	t.Helper()
+
+	started := make(chan struct{})
+	ch := make(chan struct{})
+	async.Submit(exec, func(context.Context) (struct{}, error) {
+		close(started)
+		<-ch
+		return struct{}{}, nil
+	})
+	<-started
+
+	return func() { close(ch) }
+}
+
+func TestExecutorSubmit(t *testing.T) {
+	exec := async.NewExecutor(2)
+	defer exec.Shutdown(context.Background())
+
+	expectedErr := errors.New("failed")
+
+	ok := async.Submit(exec, func(context.Context) (int, error) { return 42, nil })
+	fail := async.Submit(exec, func(context.Context) (int, error) { return 0, expectedErr })
+
+	v, err := ok.Result()
+	require.NoError(t, err)
+	assert.Equal(t, 42, v)
+
+	_, err = fail.Result()
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestExecutorDefaultWorkers(t *testing.T) {
+	exec := async.NewExecutor(0)
+	defer exec.Shutdown(context.Background())
+
+	v, err := async.Submit(exec, func(context.Context) (string, error) { return "ok", nil }).Result()
+	require.NoError(t, err)
+	assert.Equal(t, "ok", v)
+}
+
+func TestExecutorBoundedConcurrency(t *testing.T) {
+	const workers = 3
+
+	exec := async.NewExecutor(workers, async.WithQueueSize(100))
+	defer exec.Shutdown(context.Background())
+
+	var running, peak atomic.Int32
+	futures := make([]*async.Future[int], 0, 50)
+
+	for i := 0; i < 50; i++ {
+		futures = append(futures, async.Submit(exec, func(context.Context) (int, error) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return i, nil
+		}))
+	}
+
+	values, err := async.All(context.Background(), futures...)
+	require.NoError(t, err)
+	assert.Len(t, values, 50)
+	assert.LessOrEqual(t, peak.Load(), int32(workers))
+}
+
+func TestExecutorSemaphore(t *testing.T) {
+	sem := syncx.NewSemaphore(1)
+	exec1 := async.NewExecutor(2, async.WithSemaphore(sem), async.WithQueueSize(10))
+	exec2 := async.NewExecutor(2, async.WithSemaphore(sem), async.WithQueueSize(10))
+	defer exec1.Shutdown(context.Background())
+	defer exec2.Shutdown(context.Background())
+
+	var running, peak atomic.Int32
+	task := func(context.Context) (struct{}, error) {
+		if n := running.Add(1); n > peak.Load() {
+			peak.Store(n)
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return struct{}{}, nil
+	}
+
+	futures := make([]*async.Future[struct{}], 0, 20)
+	for i := 0; i < 10; i++ {
+		futures = append(futures, async.Submit(exec1, task), async.Submit(exec2, task))
+	}
+
+	_, err := async.All(context.Background(), futures...)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), peak.Load())
+}
+
+func TestExecutorOverflowPolicy(t *testing.T) {
+	t.Run("block", func(t *testing.T) {
+		exec := async.NewExecutor(1)
+		defer exec.Shutdown(context.Background())
+
+		release := blockWorker(t, exec)
+
+		submitted := make(chan *async.Future[int])
+		go func() {
+			submitted <- async.Submit(exec, func(context.Context) (int, error) { return 1, nil })
+		}()
+
+		select {
+		case <-submitted:
+			t.Fatal("Submit must block while the queue is full")
+		case <-time.After(time.Millisecond * 20):
+		}
+
+		release()
+		assert.Equal(t, 1, (<-submitted).Get())
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		exec := async.NewExecutor(1, async.WithQueueSize(1), async.WithOverflowPolicy(async.OverflowReject))
+		defer exec.Shutdown(context.Background())
+
+		release := blockWorker(t, exec)
+
+		queued := async.Submit(exec, func(context.Context) (int, error) { return 5, nil })
+		_, err := async.Submit(exec, func(context.Context) (int, error) { return 1, nil }).Result()
+		assert.ErrorIs(t, err, async.ErrRejected)
+
+		release()
+		assert.Equal(t, 5, queued.Get())
+	})
+
+	t.Run("caller runs", func(t *testing.T) {
+		exec := async.NewExecutor(1, async.WithQueueSize(1), async.WithOverflowPolicy(async.OverflowCallerRuns))
+		defer exec.Shutdown(context.Background())
+
+		release := blockWorker(t, exec)
+		defer release()
+
+		async.Submit(exec, func(context.Context) (int, error) { return 0, nil })
+
+		future := async.Submit(exec, func(context.Context) (int, error) { return 1, nil })
+
+		v, ok := future.TryGet()
+		assert.True(t, ok, "the task must run synchronously in the caller")
+		assert.Equal(t, 1, v)
+	})
+
+	t.Run("caller runs holds the semaphore", func(t *testing.T) {
+		sem := syncx.NewSemaphore(1)
+		exec := async.NewExecutor(1, async.WithSemaphore(sem), async.WithOverflowPolicy(async.OverflowCallerRuns))
+		defer exec.Shutdown(context.Background())
+
+		release := blockWorker(t, exec)
+
+		var ran atomic.Bool
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			async.Submit(exec, func(context.Context) (int, error) {
+				ran.Store(true)
+				return 1, nil
+			})
+		}()
+
+		time.Sleep(time.Millisecond * 20)
+		assert.False(t, ran.Load(), "the caller must wait for the semaphore held by the worker")
+
+		release()
+		<-done
+		assert.True(t, ran.Load())
+	})
+}
+
+func TestExecutorIdleWorkers(t *testing.T) {
+	for _, policy := range []async.OverflowPolicy{async.OverflowReject, async.OverflowCallerRuns} {
+		exec := async.NewExecutor(4, async.WithOverflowPolicy(policy))
+		release := make(chan struct{})
+
+		// A task run by the caller would block Submit until release.
+		submitted := make(chan []*async.Future[int])
+		go func() {
+			futures := make([]*async.Future[int], 0, 4)
+			for i := range 4 {
+				futures = append(futures, async.Submit(exec, func(context.Context) (int, error) {
+					<-release
+					return i, nil
+				}))
+			}
+			submitted <- futures
+		}()
+
+		var futures []*async.Future[int]
+		select {
+		case futures = <-submitted:
+		case <-time.After(time.Second):
+			t.Fatal("a task was run by the caller although workers were idle")
+		}
+		close(release)
+
+		for i, f := range futures {
+			v, err := f.Result()
+			require.NoError(t, err, "an idle worker must count as capacity")
+			assert.Equal(t, i, v)
+		}
+		require.NoError(t, exec.Shutdown(context.Background()))
+	}
+}
+
+func TestExecutorPanic(t *testing.T) {
+	exec := async.NewExecutor(1)
+	defer exec.Shutdown(context.Background())
+
+	_, err := async.Submit(exec, func(context.Context) (int, error) { panic("boom") }).Result()
+
+	var panicErr *async.PanicError
+	require.ErrorAs(t, err, &panicErr)
+
+	// The worker survives the panic.
+	v, err := async.Submit(exec, func(context.Context) (int, error) { return 1, nil }).Result()
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func TestExecutorShutdown(t *testing.T) {
+	t.Run("waits for queued tasks", func(t *testing.T) {
+		exec := async.NewExecutor(1, async.WithQueueSize(10))
+
+		var done atomic.Int32
+		for i := 0; i < 10; i++ {
+			async.Submit(exec, func(context.Context) (struct{}, error) {
+				time.Sleep(time.Millisecond)
+				done.Add(1)
+				return struct{}{}, nil
+			})
+		}
+
+		require.NoError(t, exec.Shutdown(context.Background()))
+		assert.Equal(t, int32(10), done.Load())
+		require.NoError(t, exec.Shutdown(context.Background()), "Shutdown may be called again")
+
+		_, err := async.Submit(exec, func(context.Context) (int, error) { return 1, nil }).Result()
+		assert.ErrorIs(t, err, async.ErrExecutorShutdown)
+	})
+
+	t.Run("ctx done", func(t *testing.T) {
+		exec := async.NewExecutor(1)
+		release := blockWorker(t, exec)
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+
+		assert.ErrorIs(t, exec.Shutdown(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("ctx done with a blocked Submit", func(t *testing.T) {
+		exec := async.NewExecutor(1)
+		release := blockWorker(t, exec)
+		defer release()
+
+		blocked := make(chan *async.Future[int])
+		go func() {
+			blocked <- async.Submit(exec, func(context.Context) (int, error) { return 1, nil })
+		}()
+		time.Sleep(time.Millisecond * 10) // let the Submit block on the busy worker
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancel()
+
+		start := time.Now()
+		assert.ErrorIs(t, exec.Shutdown(ctx), context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Millisecond*500, "Shutdown must respect its deadline")
+
+		_, err := (<-blocked).Result()
+		assert.ErrorIs(t, err, async.ErrExecutorShutdown)
+
+		_, err = async.Submit(exec, func(context.Context) (int, error) { return 2, nil }).Result()
+		assert.ErrorIs(t, err, async.ErrExecutorShutdown)
+	})
+}
+
+func TestExecutorShutdownNow(t *testing.T) {
+	exec := async.NewExecutor(1, async.WithQueueSize(1))
+
+	started := make(chan struct{})
+	running := async.Submit(exec, func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	<-started
+
+	queued := async.Submit(exec, func(context.Context) (int, error) { return 1, nil })
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var blocked *async.Future[int]
+	go func() {
+		defer wg.Done()
+		blocked = async.Submit(exec, func(context.Context) (int, error) { return 2, nil })
+	}()
+	time.Sleep(time.Millisecond * 10) // let the third Submit block on the full queue
+
+	exec.ShutdownNow()
+	wg.Wait()
+
+	_, err := running.Result()
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = queued.Result()
+	assert.ErrorIs(t, err, async.ErrExecutorShutdown)
+
+	_, err = blocked.Result()
+	assert.ErrorIs(t, err, async.ErrExecutorShutdown)
+
+	require.NoError(t, exec.Shutdown(context.Background()))
+}
@@ -0,0 +1,158 @@
+package async
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// FakeClock is a Clock whose time moves only when Advance or Set is called.
+// Timers created by it fire when the clock passes their deadline, which makes
+// code built on After, At, Every, Debounce and Throttle testable without sleeping.
+//
+// All methods are safe for concurrent use by multiple goroutines.
+//
+// Example usage:
+//
+//	clock := async.NewFakeClock(time.Now())
+//	future := async.After(time.Minute, func() int { return 42 }, async.WithClock(clock))
+//
+//	clock.WaitForTimers(1) // the timer of After is armed
+//	clock.Advance(time.Minute)
+//	fmt.Println(future.Get()) // Output: 42
+type FakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers map[*fakeTimer]struct{}
+}
+
+var _ Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{
+		now:    now,
+		timers: make(map[*fakeTimer]struct{}),
+	}
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTimer creates a Timer that fires once the clock is advanced by d.
+// A timer with d <= 0 fires immediately.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{
+		clock: c,
+		c:     make(chan time.Time, 1),
+	}
+	t.Reset(d)
+
+	return t
+}
+
+// Advance moves the clock forward by d and fires, in deadline order,
+// every timer whose deadline is not after the new time.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to t and fires, in deadline order,
+// every timer whose deadline is not after t. Moving the clock backwards fires nothing.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = t
+
+	due := make([]*fakeTimer, 0, len(c.timers))
+	for timer := range c.timers {
+		if !timer.deadline.After(t) {
+			due = append(due, timer)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].deadline.Before(due[j].deadline) })
+	for _, timer := range due {
+		c.fire(timer)
+	}
+}
+
+// WaitForTimers blocks until at least n timers of the clock are active.
+// Tests use it to wait until the code under test has armed its timers before advancing the clock.
+func (c *FakeClock) WaitForTimers(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// fire delivers the current time to timer and deactivates it. c.mu must be held.
+func (c *FakeClock) fire(timer *fakeTimer) {
+	delete(c.timers, timer)
+
+	select {
+	case timer.c <- c.now:
+	default:
+	}
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	c        chan time.Time
+	deadline time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.stop()
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.stop()
+
+	t.deadline = t.clock.now.Add(d)
+	if d <= 0 {
+		t.clock.fire(t)
+		return active
+	}
+
+	t.clock.timers[t] = struct{}{}
+	t.clock.cond.Broadcast()
+
+	return active
+}
+
+// stop deactivates the timer and drains a pending value, like time.Timer since Go 1.23.
+// t.clock.mu must be held.
+func (t *fakeTimer) stop() bool {
+	_, active := t.clock.timers[t]
+	delete(t.clock.timers, t)
+
+	select {
+	case <-t.c:
+	default:
+	}
+
+	return active
+}
@@ -0,0 +1,98 @@
+package async_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lif0/pkg/async"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func fired(timer async.Timer) bool {
+	select {
+	case <-timer.C():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	t.Run("now", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		assert.Equal(t, epoch, clock.Now())
+
+		clock.Advance(time.Hour)
+		assert.Equal(t, epoch.Add(time.Hour), clock.Now())
+	})
+
+	t.Run("timer fires at deadline", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		timer := clock.NewTimer(time.Second)
+
+		clock.Advance(time.Millisecond * 999)
+		assert.False(t, fired(timer))
+
+		clock.Advance(time.Millisecond)
+		assert.True(t, fired(timer))
+
+		clock.Advance(time.Hour)
+		assert.False(t, fired(timer), "a timer fires only once")
+	})
+
+	t.Run("stop and reset", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		timer := clock.NewTimer(time.Second)
+
+		assert.True(t, timer.Stop())
+		assert.False(t, timer.Stop())
+		clock.Advance(time.Second)
+		assert.False(t, fired(timer))
+
+		assert.False(t, timer.Reset(time.Second))
+		assert.True(t, timer.Reset(time.Minute))
+		clock.Advance(time.Second)
+		assert.False(t, fired(timer))
+		clock.Advance(time.Minute)
+		assert.True(t, fired(timer))
+	})
+
+	t.Run("reset drains a pending value", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		timer := clock.NewTimer(time.Second)
+		clock.Advance(time.Second)
+
+		timer.Reset(time.Second)
+		assert.False(t, fired(timer))
+	})
+
+	t.Run("non-positive duration fires immediately", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		assert.True(t, fired(clock.NewTimer(0)))
+	})
+
+	t.Run("set fires in deadline order", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		late := clock.NewTimer(time.Minute)
+		early := clock.NewTimer(time.Second)
+
+		clock.Set(epoch.Add(time.Hour))
+		assert.Equal(t, epoch.Add(time.Hour), <-early.C())
+		assert.Equal(t, epoch.Add(time.Hour), <-late.C())
+	})
+
+	t.Run("wait for timers", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+
+		go func() {
+			time.Sleep(time.Millisecond * 10)
+			clock.NewTimer(time.Second)
+			clock.NewTimer(time.Second)
+		}()
+
+		clock.WaitForTimers(2)
+	})
+}
@@ -1,12 +1,20 @@
 package async
 
-// FutureAction is an abstraction over a channel that models a task and its result.
+import (
+	"context"
+	"sync/atomic"
+)
+
+// FutureAction models a task and its result.
 // It allows executing a computation asynchronously in a goroutine and retrieving
 // the result later via a blocking call. This is similar to the Future pattern in
 // other languages, providing a simple way to handle asynchronous results without
 // manual channel management.
 //
-// The channel is closed after the result is sent, ensuring proper resource cleanup.
+// FutureAction embeds the Future settled by the action, so Get, Result, GetContext,
+// GetTimeout, TryGet and Done are available on it, and any number of goroutines may read the result.
+// If the action blocks indefinitely (e.g., due to an infinite loop or deadlock),
+// Get never returns; use GetContext or GetTimeout to bound the wait.
 //
 // Example usage:
 //
@@ -21,31 +29,128 @@ package async
 //		fmt.Println(result) // Output: success
 //	}
 type FutureAction[T any] struct {
-	result chan T
+	*Future[T]
+
+	cancel  context.CancelFunc
+	outcome atomic.Int32
+}
+
+const (
+	outcomePending int32 = iota
+	outcomeCompleted
+	outcomeCancelled
+)
+
+// FutureActionOption configures a FutureAction.
+type FutureActionOption func(*futureActionOptions)
+
+type futureActionOptions struct {
+	recoverPanic bool
+}
+
+// WithPanicRecovery makes the FutureAction recover a panic raised by its action.
+// The recovered value is wrapped in a *PanicError together with the stack trace
+// and returned by Result, GetContext and GetTimeout; Get returns the zero value.
+// Without this option a panicking action crashes the process.
+func WithPanicRecovery() FutureActionOption {
+	return func(o *futureActionOptions) {
+		o.recoverPanic = true
+	}
 }
 
 // NewFutureAction creates and returns a new FutureAction.
 // It starts the provided action function in a separate goroutine.
-// The action's return value is sent to the internal channel.
-// The channel is closed after sending the result to allow safe ranging or detection of completion.
-func NewFutureAction[T any](action func() T) *FutureAction[T] {
+// The action's return value settles the embedded Future.
+func NewFutureAction[T any](action func() T, opts ...FutureActionOption) *FutureAction[T] {
+	return newFutureAction(context.Background(), false, func(context.Context) (T, error) { return action(), nil }, opts)
+}
+
+// NewFutureActionErr creates and returns a new FutureAction for an action that
+// returns a value together with an error, the usual (T, error) shape of Go functions.
+// It starts the provided action function in a separate goroutine.
+// Get returns only the value; use Result or GetContext to observe the error as well.
+func NewFutureActionErr[T any](action func() (T, error), opts ...FutureActionOption) *FutureAction[T] {
+	return newFutureAction(context.Background(), false, func(context.Context) (T, error) { return action() }, opts)
+}
+
+// NewFutureActionContext creates and returns a new cancellable FutureAction.
+// It starts the provided action function in a separate goroutine and passes it
+// a context derived from ctx, which is canceled by Cancel or when ctx is done.
+//
+// If the derived context is done before the action returns, the FutureAction is settled
+// at once with the zero value and context.Cause of the context, and Cancelled reports true;
+// the late result of the action is discarded. The action should observe its context
+// and return promptly once it is done, so that its goroutine does not leak.
+//
+// Example usage:
+//
+//	future := async.NewFutureActionContext(ctx, func(ctx context.Context) Response {
+//		return hedgedCall(ctx)
+//	})
+//	defer future.Cancel() // abandon the work if nobody needs it anymore
+func NewFutureActionContext[T any](ctx context.Context, action func(ctx context.Context) T, opts ...FutureActionOption) *FutureAction[T] {
+	return newFutureAction(ctx, true, func(ctx context.Context) (T, error) { return action(ctx), nil }, opts)
+}
+
+// Cancel cancels the context of an action created by NewFutureActionContext.
+// If the action has not finished yet, the FutureAction is settled with the cancellation error.
+// Cancel may be called multiple times and from multiple goroutines.
+// For actions created by NewFutureAction or NewFutureActionErr, which take no context, Cancel does nothing.
+func (f *FutureAction[T]) Cancel() {
+	if f.cancel != nil {
+		f.cancel()
+	}
+}
+
+// Cancelled reports whether the FutureAction was settled by cancellation rather than
+// by its action returning. It returns false while the FutureAction is still pending.
+func (f *FutureAction[T]) Cancelled() bool {
+	return f.outcome.Load() == outcomeCancelled
+}
+
+// newFutureAction starts action in a goroutine. A cancellable action receives a context
+// derived from ctx; otherwise it receives ctx itself and Cancel does nothing.
+func newFutureAction[T any](ctx context.Context, cancellable bool, action func(context.Context) (T, error), opts []FutureActionOption) *FutureAction[T] {
+	var o futureActionOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	future := &FutureAction[T]{
-		result: make(chan T, 1),
+		Future: &Future[T]{state: newState[T]()},
+	}
+
+	if cancellable {
+		ctx, future.cancel = context.WithCancel(ctx)
+		context.AfterFunc(ctx, func() { future.abandon(ctx) })
+	}
+
+	run := func() (T, error) { return action(ctx) }
+	if o.recoverPanic {
+		run = withRecover(run)
 	}
 
 	go func() {
-		defer close(future.result)
-		future.result <- action()
+		v, err := run()
+
+		if cancellable && ctx.Err() != nil {
+			future.abandon(ctx)
+		} else if future.outcome.CompareAndSwap(outcomePending, outcomeCompleted) {
+			future.state.settle(v, err)
+		}
+
+		if future.cancel != nil {
+			future.cancel() // release the context of the action
+		}
 	}()
 
 	return future
 }
 
-// Get returns the result of the asynchronous task.
-// This method blocks until the result is available from the channel.
-// If the action function blocks indefinitely (e.g., due to an infinite loop or deadlock),
-// Get will never return, potentially causing the caller to hang.
-// It is the caller's responsibility to ensure the action completes.
-func (f *FutureAction[T]) Get() T {
-	return <-f.result
+// abandon settles the FutureAction with the cancellation cause of ctx, unless it is already settled.
+func (f *FutureAction[T]) abandon(ctx context.Context) {
+	if f.outcome.CompareAndSwap(outcomePending, outcomeCancelled) {
+		var zero T
+		f.state.settle(zero, context.Cause(ctx))
+	}
 }
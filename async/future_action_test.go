@@ -1,6 +1,8 @@
 package async_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -10,13 +12,271 @@ import (
 )
 
 func Test_FutureAction(t *testing.T) {
+	clock := async.NewFakeClock(time.Now())
+	timer := clock.NewTimer(time.Second)
 	callback := func() any {
-		time.Sleep(time.Millisecond)
+		<-timer.C()
 		return "success"
 	}
 
 	future := async.NewFutureAction(callback)
-	time.Sleep(time.Millisecond * 500)
+	_, ok := future.TryGet()
+	assert.False(t, ok)
+
+	clock.Advance(time.Second)
 	result := future.Get()
 	assert.Equal(t, "success", result)
 }
+
+func Test_FutureAction_GetContext(t *testing.T) {
+	t.Run("resolved", func(t *testing.T) {
+		future := async.NewFutureAction(func() int { return 42 })
+
+		v, err := future.GetContext(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 42, v)
+	})
+
+	t.Run("ctx canceled", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		future := async.NewFutureAction(func() int {
+			<-release
+			return 42
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		v, err := future.GetContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, v)
+	})
+}
+
+func Test_FutureAction_GetTimeout(t *testing.T) {
+	t.Run("resolved", func(t *testing.T) {
+		future := async.NewFutureAction(func() string { return "success" })
+
+		v, err := future.GetTimeout(time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, "success", v)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		future := async.NewFutureAction(func() string {
+			<-release
+			return "success"
+		})
+
+		v, err := future.GetTimeout(time.Millisecond * 10)
+		assert.ErrorIs(t, err, async.ErrTimeout)
+		assert.Empty(t, v)
+	})
+}
+
+func Test_FutureAction_TryGet(t *testing.T) {
+	release := make(chan struct{})
+	future := async.NewFutureAction(func() int {
+		<-release
+		return 7
+	})
+
+	v, ok := future.TryGet()
+	assert.False(t, ok)
+	assert.Zero(t, v)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		v, ok = future.TryGet()
+		return ok
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 7, v)
+}
+
+func Test_FutureActionErr(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		future := async.NewFutureActionErr(func() (int, error) { return 42, nil })
+
+		v, err := future.Result()
+		assert.NoError(t, err)
+		assert.Equal(t, 42, v)
+	})
+
+	t.Run("value and error", func(t *testing.T) {
+		expectedErr := errors.New("partial")
+		future := async.NewFutureActionErr(func() (int, error) { return 1, expectedErr })
+
+		v, err := future.GetContext(context.Background())
+		assert.ErrorIs(t, err, expectedErr)
+		assert.Equal(t, 1, v)
+	})
+
+	t.Run("GetTimeout", func(t *testing.T) {
+		expectedErr := errors.New("failed")
+		future := async.NewFutureActionErr(func() (string, error) { return "", expectedErr })
+
+		_, err := future.GetTimeout(time.Second)
+		assert.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("Get ignores error", func(t *testing.T) {
+		future := async.NewFutureActionErr(func() (string, error) { return "value", errors.New("failed") })
+		assert.Equal(t, "value", future.Get())
+	})
+}
+
+func Test_FutureAction_WithPanicRecovery(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		future := async.NewFutureAction(func() int {
+			panic("plugin failed")
+		}, async.WithPanicRecovery())
+
+		v, err := future.GetContext(context.Background())
+		assert.Zero(t, v)
+
+		var panicErr *async.PanicError
+		if assert.ErrorAs(t, err, &panicErr) {
+			assert.Equal(t, "plugin failed", panicErr.Value)
+			assert.Contains(t, string(panicErr.Stack), "future_action_test.go")
+		}
+	})
+
+	t.Run("panic with error", func(t *testing.T) {
+		cause := errors.New("boom")
+		future := async.NewFutureActionErr(func() (string, error) {
+			panic(cause)
+		}, async.WithPanicRecovery())
+
+		v, err := future.Result()
+		assert.Empty(t, v)
+		assert.ErrorIs(t, err, cause)
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		future := async.NewFutureAction(func() int { return 1 }, async.WithPanicRecovery())
+
+		v, err := future.Result()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v)
+	})
+}
+
+func Test_FutureAction_MultipleReaders(t *testing.T) {
+	future := async.NewFutureAction(func() int { return 42 })
+
+	<-future.Done()
+	assert.Equal(t, 42, future.Get())
+	assert.Equal(t, 42, future.Get())
+
+	v, ok := future.TryGet()
+	assert.True(t, ok)
+	assert.Equal(t, 42, v)
+}
+
+func Test_FutureActionContext(t *testing.T) {
+	t.Run("completed", func(t *testing.T) {
+		future := async.NewFutureActionContext(context.Background(), func(ctx context.Context) int {
+			return 42
+		})
+
+		v, err := future.Result()
+		assert.NoError(t, err)
+		assert.Equal(t, 42, v)
+		assert.False(t, future.Cancelled())
+
+		future.Cancel() // no effect after completion
+		assert.Equal(t, 42, future.Get())
+		assert.False(t, future.Cancelled())
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		stopped := make(chan struct{})
+		future := async.NewFutureActionContext(context.Background(), func(ctx context.Context) int {
+			defer close(stopped)
+			<-ctx.Done()
+			return 42
+		})
+
+		assert.False(t, future.Cancelled())
+		future.Cancel()
+
+		v, err := future.Result()
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, v)
+		assert.True(t, future.Cancelled())
+
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Error("action did not observe cancellation")
+		}
+	})
+
+	t.Run("parent ctx done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+
+		future := async.NewFutureActionContext(ctx, func(ctx context.Context) string {
+			<-ctx.Done()
+			return "late"
+		})
+
+		_, err := future.Result()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, future.Cancelled())
+	})
+
+	t.Run("context released after completion", func(t *testing.T) {
+		var actionCtx context.Context
+		future := async.NewFutureActionContext(context.Background(), func(ctx context.Context) int {
+			actionCtx = ctx
+			return 1
+		})
+
+		<-future.Done()
+		assert.Eventually(t, func() bool { return actionCtx.Err() != nil }, time.Second, time.Millisecond)
+		assert.False(t, future.Cancelled())
+	})
+
+	t.Run("panic recovery", func(t *testing.T) {
+		future := async.NewFutureActionContext(context.Background(), func(ctx context.Context) int {
+			panic("boom")
+		}, async.WithPanicRecovery())
+
+		var panicErr *async.PanicError
+		_, err := future.Result()
+		assert.ErrorAs(t, err, &panicErr)
+	})
+
+	t.Run("cancel without context", func(t *testing.T) {
+		future := async.NewFutureAction(func() int { return 1 })
+		future.Cancel()
+
+		assert.Equal(t, 1, future.Get())
+		assert.False(t, future.Cancelled())
+	})
+}
+
+func Test_FutureAction_OnComplete(t *testing.T) {
+	release := make(chan struct{})
+	future := async.NewFutureActionErr(func() (int, error) {
+		<-release
+		return 42, nil
+	})
+
+	done := make(chan int)
+	future.OnSuccess(func(v int) { done <- v })
+	close(release)
+
+	select {
+	case v := <-done:
+		assert.Equal(t, 42, v)
+	case <-time.After(time.Second):
+		t.Fatal("OnSuccess was not called")
+	}
+}
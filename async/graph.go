@@ -0,0 +1,311 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/lif0/pkg/errx"
+)
+
+// FailureMode selects what a Graph does when a node fails.
+type FailureMode int
+
+const (
+	// FailureSkipDependents skips the nodes that depend on a failed node, directly or transitively.
+	// Nodes that do not depend on it still run.
+	FailureSkipDependents FailureMode = iota
+	// FailureCancelAll cancels the context of the running nodes and skips every node not yet started.
+	FailureCancelAll
+)
+
+// GraphOption configures a Graph.
+type GraphOption func(*graphOptions)
+
+type graphOptions struct {
+	mode FailureMode
+}
+
+// WithFailureMode sets what a Graph does when a node fails. The default is FailureSkipDependents.
+func WithFailureMode(mode FailureMode) GraphOption {
+	return func(o *graphOptions) {
+		o.mode = mode
+	}
+}
+
+// Graph runs a set of named tasks (nodes) that depend on each other. Run starts every node
+// as soon as all of its dependencies have succeeded, so independent nodes run in parallel.
+// The result of each node is exposed as a Future, which lets a node read the results of its
+// dependencies without blocking.
+//
+// Run checks the graph before starting anything: a dependency cycle, a duplicate node name
+// or a dependency on an unknown node makes it fail with ErrInvalidGraph.
+// A node that does not run is settled with ErrSkipped. A panic in a node is recovered and
+// reported as a *PanicError.
+//
+// Nodes must be added before Run is called, and a Graph must not be run more than once.
+//
+// Example usage:
+//
+//	g := async.NewGraph()
+//	cfg := async.AddNode(g, "config", func(ctx context.Context) (*Config, error) {
+//		return loadConfig(ctx)
+//	})
+//	db := async.AddNode(g, "db", func(ctx context.Context) (*sql.DB, error) {
+//		return openDB(ctx, cfg.Get().DSN)
+//	}, "config")
+//	g.Add("migrate", func(ctx context.Context) error {
+//		return migrate(ctx, db.Get())
+//	}, "db")
+//
+//	if err := g.Run(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+type Graph struct {
+	mode    FailureMode
+	nodes   []*graphNode
+	byName  map[string]*graphNode
+	invalid errx.MultiError
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	errs    errx.MultiError
+	skipped bool
+}
+
+type graphNode struct {
+	name string
+	deps []string
+	run  func(ctx context.Context) error
+	skip func(err error)
+
+	dependents []*graphNode
+	pending    int
+	blocked    bool
+}
+
+// NewGraph returns an empty Graph. It accepts WithFailureMode.
+func NewGraph(opts ...GraphOption) *Graph {
+	var o graphOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Graph{
+		mode:   o.mode,
+		byName: make(map[string]*graphNode),
+	}
+}
+
+// AddNode adds a node named name to g that runs fn once all of deps have succeeded,
+// and returns the Future of its result. The Future is settled when the node runs,
+// is skipped, or Run finds the graph invalid.
+func AddNode[T any](g *Graph, name string, fn func(ctx context.Context) (T, error), deps ...string) *Future[T] {
+	s := newState[T]()
+
+	n := &graphNode{
+		name: name,
+		deps: deps,
+		run: func(ctx context.Context) error {
+			v, err := withRecover(func() (T, error) {
+				return fn(ctx)
+			})()
+			s.settle(v, err)
+			return err
+		},
+		skip: func(err error) {
+			var zero T
+			s.settle(zero, err)
+		},
+	}
+
+	if _, ok := g.byName[name]; ok {
+		err := fmt.Errorf("%w: duplicate node %q", ErrInvalidGraph, name)
+		g.invalid.Append(err)
+		n.skip(err)
+	} else {
+		g.byName[name] = n
+		g.nodes = append(g.nodes, n)
+	}
+
+	return &Future[T]{state: s}
+}
+
+// Add adds a node named name to g that runs fn once all of deps have succeeded.
+// It is AddNode for nodes that produce no value.
+func (g *Graph) Add(name string, fn func(ctx context.Context) error, deps ...string) *Future[struct{}] {
+	return AddNode(g, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, deps...)
+}
+
+// Run runs the nodes of g and blocks until every node has run or been skipped.
+// The context passed to the nodes is derived from ctx.
+//
+// It returns nil if every node succeeded. If the graph is invalid, no node runs and Run returns
+// an errx.MultiError wrapping ErrInvalidGraph. Otherwise it returns an errx.MultiError with the
+// errors of the failed nodes in the order they occurred, or the context error if ctx was done
+// before every node could run.
+func (g *Graph) Run(ctx context.Context) error {
+	if err := g.validate(); err != nil {
+		for _, n := range g.nodes {
+			n.skip(err)
+		}
+		return err
+	}
+
+	g.ctx, g.cancel = context.WithCancel(ctx)
+	defer g.cancel()
+
+	g.wg.Add(len(g.nodes))
+
+	g.mu.Lock()
+	for _, n := range g.nodes {
+		if n.pending == 0 {
+			g.start(n)
+		}
+	}
+	g.mu.Unlock()
+
+	g.wg.Wait()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.errs.IsEmpty() {
+		return g.errs
+	}
+
+	if g.skipped {
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// start runs n in a new goroutine, or skips it if a dependency failed or the graph was cancelled.
+// g.mu must be held.
+func (g *Graph) start(n *graphNode) {
+	if n.blocked || g.ctx.Err() != nil {
+		g.skipped = true
+		n.skip(ErrSkipped)
+		g.resolve(n, false)
+		return
+	}
+
+	go func() {
+		err := n.run(g.ctx)
+
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		if err != nil {
+			g.errs.Append(fmt.Errorf("node %q: %w", n.name, err))
+			if g.mode == FailureCancelAll {
+				g.cancel()
+			}
+		}
+
+		g.resolve(n, err == nil)
+	}()
+}
+
+// resolve records that n has finished and starts the dependents that have no pending dependencies left.
+// g.mu must be held.
+func (g *Graph) resolve(n *graphNode, ok bool) {
+	defer g.wg.Done()
+
+	for _, d := range n.dependents {
+		if !ok {
+			d.blocked = true
+		}
+
+		d.pending--
+		if d.pending == 0 {
+			g.start(d)
+		}
+	}
+}
+
+// validate links every node to its dependents and checks that the graph is acyclic
+// and that every dependency exists.
+func (g *Graph) validate() error {
+	errs := slices.Clone(g.invalid)
+
+	for _, n := range g.nodes {
+		n.dependents, n.pending, n.blocked = nil, 0, false
+	}
+
+	for _, n := range g.nodes {
+		for _, name := range n.deps {
+			d, ok := g.byName[name]
+			if !ok {
+				errs.Append(fmt.Errorf("%w: node %q depends on unknown node %q", ErrInvalidGraph, n.name, name))
+				continue
+			}
+
+			d.dependents = append(d.dependents, n)
+			n.pending++
+		}
+	}
+
+	if errs.IsEmpty() {
+		errs.Append(g.findCycle())
+	}
+
+	if errs.IsEmpty() {
+		return nil
+	}
+
+	return errs
+}
+
+// findCycle returns an error naming the nodes of a dependency cycle, or nil if there is none.
+func (g *Graph) findCycle() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	color := make(map[*graphNode]int, len(g.nodes))
+	var path []string
+
+	var visit func(n *graphNode) error
+	visit = func(n *graphNode) error {
+		color[n] = visiting
+		path = append(path, n.name)
+
+		for _, name := range n.deps {
+			d := g.byName[name]
+
+			switch color[d] {
+			case visiting:
+				cycle := append(path[slices.Index(path, name):], name)
+				return fmt.Errorf("%w: dependency cycle %s", ErrInvalidGraph, strings.Join(cycle, " -> "))
+			case unvisited:
+				if err := visit(d); err != nil {
+					return err
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		color[n] = visited
+		return nil
+	}
+
+	for _, n := range g.nodes {
+		if color[n] == unvisited {
+			if err := visit(n); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+	"github.com/lif0/pkg/errx"
+)
+
+func errOf[T any](f *async.Future[T]) error {
+	_, err := f.Result()
+	return err
+}
+
+func TestGraph(t *testing.T) {
+	t.Run("topological order", func(t *testing.T) {
+		var mu sync.Mutex
+		var order []string
+		record := func(name string) func(ctx context.Context) error {
+			return func(ctx context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, name)
+				return nil
+			}
+		}
+
+		g := async.NewGraph()
+		g.Add("d", record("d"), "b", "c")
+		g.Add("b", record("b"), "a")
+		g.Add("c", record("c"), "a")
+		g.Add("a", record("a"))
+
+		require.NoError(t, g.Run(context.Background()))
+		require.Len(t, order, 4)
+		assert.Equal(t, "a", order[0])
+		assert.ElementsMatch(t, []string{"b", "c"}, order[1:3])
+		assert.Equal(t, "d", order[3])
+	})
+
+	t.Run("results", func(t *testing.T) {
+		g := async.NewGraph()
+		x := async.AddNode(g, "x", func(ctx context.Context) (int, error) { return 2, nil })
+		y := async.AddNode(g, "y", func(ctx context.Context) (int, error) { return 3, nil })
+		sum := async.AddNode(g, "sum", func(ctx context.Context) (int, error) {
+			return x.Get() + y.Get(), nil
+		}, "x", "y")
+
+		require.NoError(t, g.Run(context.Background()))
+		assert.Equal(t, 5, sum.Get())
+	})
+
+	t.Run("independent nodes run in parallel", func(t *testing.T) {
+		var wg sync.WaitGroup
+		wg.Add(3)
+		task := func(ctx context.Context) error {
+			wg.Done()
+			wg.Wait() // returns only once all three nodes are running
+			return nil
+		}
+
+		g := async.NewGraph()
+		g.Add("a", task)
+		g.Add("b", task)
+		g.Add("c", task)
+
+		done := make(chan error, 1)
+		go func() { done <- g.Run(context.Background()) }()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("independent nodes did not run in parallel")
+		}
+	})
+
+	t.Run("skip dependents", func(t *testing.T) {
+		fail := errors.New("failed")
+		var ran atomic.Int32
+
+		g := async.NewGraph()
+		g.Add("a", func(ctx context.Context) error { return fail })
+		b := g.Add("b", func(ctx context.Context) error { ran.Add(1); return nil }, "a")
+		c := g.Add("c", func(ctx context.Context) error { ran.Add(1); return nil }, "b")
+		d := g.Add("d", func(ctx context.Context) error { ran.Add(1); return nil })
+
+		err := g.Run(context.Background())
+
+		var errs errx.MultiError
+		require.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 1)
+		assert.ErrorIs(t, err, fail)
+
+		assert.Equal(t, int32(1), ran.Load(), "only the independent node must run")
+		assert.ErrorIs(t, errOf(b), async.ErrSkipped)
+		assert.ErrorIs(t, errOf(c), async.ErrSkipped)
+		assert.NoError(t, errOf(d))
+	})
+
+	t.Run("cancel all", func(t *testing.T) {
+		fail := errors.New("failed")
+		started := make(chan struct{})
+
+		g := async.NewGraph(async.WithFailureMode(async.FailureCancelAll))
+		slow := g.Add("slow", func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		g.Add("fail", func(ctx context.Context) error {
+			<-started
+			return fail
+		})
+		later := g.Add("later", func(ctx context.Context) error { return nil }, "slow")
+
+		err := g.Run(context.Background())
+		assert.ErrorIs(t, err, fail)
+		assert.ErrorIs(t, errOf(slow), context.Canceled)
+		assert.ErrorIs(t, errOf(later), async.ErrSkipped)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		g := async.NewGraph()
+		a := g.Add("a", func(ctx context.Context) error { return nil })
+
+		assert.ErrorIs(t, g.Run(ctx), context.Canceled)
+		assert.ErrorIs(t, errOf(a), async.ErrSkipped)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		g := async.NewGraph()
+		a := g.Add("a", func(ctx context.Context) error { panic("boom") })
+
+		var panicErr *async.PanicError
+		assert.ErrorAs(t, g.Run(context.Background()), &panicErr)
+		assert.ErrorAs(t, errOf(a), &panicErr)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			build   func(g *async.Graph)
+			message string
+		}{
+			{
+				name: "cycle",
+				build: func(g *async.Graph) {
+					g.Add("a", func(ctx context.Context) error { return nil }, "c")
+					g.Add("b", func(ctx context.Context) error { return nil }, "a")
+					g.Add("c", func(ctx context.Context) error { return nil }, "b")
+				},
+				message: "dependency cycle a -> c -> b -> a",
+			},
+			{
+				name: "self dependency",
+				build: func(g *async.Graph) {
+					g.Add("a", func(ctx context.Context) error { return nil }, "a")
+				},
+				message: "dependency cycle a -> a",
+			},
+			{
+				name: "unknown dependency",
+				build: func(g *async.Graph) {
+					g.Add("a", func(ctx context.Context) error { return nil }, "missing")
+				},
+				message: `node "a" depends on unknown node "missing"`,
+			},
+			{
+				name: "duplicate node",
+				build: func(g *async.Graph) {
+					g.Add("a", func(ctx context.Context) error { return nil })
+					g.Add("a", func(ctx context.Context) error { return nil })
+				},
+				message: `duplicate node "a"`,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var ran atomic.Bool
+				g := async.NewGraph()
+				tt.build(g)
+				first := g.Add("first", func(ctx context.Context) error { ran.Store(true); return nil })
+
+				err := g.Run(context.Background())
+				assert.ErrorIs(t, err, async.ErrInvalidGraph)
+				assert.ErrorContains(t, err, tt.message)
+				assert.ErrorIs(t, errOf(first), async.ErrInvalidGraph)
+				assert.False(t, ran.Load(), "no node may run in an invalid graph")
+			})
+		}
+	})
+}
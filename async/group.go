@@ -0,0 +1,133 @@
+package async
+
+import (
+	"context"
+	"sync"
+
+	"github.com/lif0/pkg/errx"
+	"github.com/lif0/pkg/syncx"
+)
+
+// GroupOption configures a Group.
+type GroupOption func(*groupOptions)
+
+type groupOptions struct {
+	limit    uint
+	failFast bool
+}
+
+// WithLimit bounds the number of tasks of a Group that run at the same time.
+// Go blocks until a slot is free. The default is 0, no limit.
+func WithLimit(n uint) GroupOption {
+	return func(o *groupOptions) {
+		o.limit = n
+	}
+}
+
+// WithFailFast makes a Group cancel its context on the first failed task,
+// and makes Wait return only that failure instead of all of them.
+func WithFailFast() GroupOption {
+	return func(o *groupOptions) {
+		o.failFast = true
+	}
+}
+
+// Group runs a set of tasks under a shared context and waits for all of them,
+// in the spirit of golang.org/x/sync/errgroup.
+//
+// By default every task runs to completion and Wait returns all failures as an errx.MultiError.
+// With WithFailFast, the first failure cancels the shared context and is the only error returned.
+// A panic in a task is recovered and reported as a *PanicError.
+//
+// A Group must be created with NewGroup and must not be reused after Wait returns.
+//
+// Example usage:
+//
+//	g, ctx := async.NewGroup(ctx, async.WithLimit(4))
+//	for _, url := range urls {
+//		g.Go(func(ctx context.Context) error {
+//			return fetch(ctx, url)
+//		})
+//	}
+//	if err := g.Wait(); err != nil {
+//		log.Println(err) // all failed fetches
+//	}
+type Group struct {
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+	sem      *syncx.Semaphore
+	failFast bool
+
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs errx.MultiError
+}
+
+// NewGroup returns a new Group and the context shared by its tasks, derived from ctx.
+// The context is canceled when Wait returns or, with WithFailFast, when the first task fails.
+func NewGroup(ctx context.Context, opts ...GroupOption) (*Group, context.Context) {
+	var o groupOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	return &Group{
+		ctx:      ctx,
+		cancel:   cancel,
+		sem:      syncx.NewSemaphore(o.limit),
+		failFast: o.failFast,
+	}, ctx
+}
+
+// Go runs fn in a new goroutine with the shared context of the Group.
+// If the Group has a limit, Go blocks until fewer than limit tasks are running.
+func (g *Group) Go(fn func(ctx context.Context) error) {
+	g.sem.Acquire()
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+		defer g.sem.Release()
+
+		_, err := withRecover(func() (struct{}, error) {
+			return struct{}{}, fn(g.ctx)
+		})()
+		if err != nil {
+			g.fail(err)
+		}
+	}()
+}
+
+// Wait blocks until all tasks started by Go have returned, then cancels the shared context.
+// It returns nil if no task failed. Otherwise it returns an errx.MultiError with the failures
+// in the order they occurred or, with WithFailFast, only the first failure.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel(context.Canceled)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.errs.IsEmpty() {
+		return nil
+	}
+
+	if g.failFast {
+		return g.errs[0]
+	}
+
+	return g.errs
+}
+
+func (g *Group) fail(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.errs.Append(err)
+
+	if g.failFast && len(g.errs) == 1 {
+		g.cancel(err)
+	}
+}
@@ -0,0 +1,115 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+	"github.com/lif0/pkg/errx"
+)
+
+func TestGroup(t *testing.T) {
+	t.Run("no failures", func(t *testing.T) {
+		g, ctx := async.NewGroup(context.Background())
+
+		var done atomic.Int32
+		for i := 0; i < 10; i++ {
+			g.Go(func(context.Context) error {
+				done.Add(1)
+				return nil
+			})
+		}
+
+		require.NoError(t, g.Wait())
+		assert.Equal(t, int32(10), done.Load())
+		assert.ErrorIs(t, ctx.Err(), context.Canceled, "Wait cancels the shared context")
+	})
+
+	t.Run("collect all failures", func(t *testing.T) {
+		g, ctx := async.NewGroup(context.Background())
+
+		err1 := errors.New("first")
+		err2 := errors.New("second")
+		g.Go(func(context.Context) error { return err1 })
+		g.Go(func(context.Context) error {
+			time.Sleep(time.Millisecond * 10)
+			return err2
+		})
+		g.Go(func(ctx context.Context) error {
+			time.Sleep(time.Millisecond * 20)
+			return ctx.Err() // the context is not canceled by failures
+		})
+
+		err := g.Wait()
+
+		var multiErr errx.MultiError
+		require.ErrorAs(t, err, &multiErr)
+		assert.Equal(t, errx.MultiError{err1, err2}, multiErr)
+		assert.Error(t, ctx.Err())
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		g, ctx := async.NewGroup(context.Background(), async.WithFailFast())
+
+		expectedErr := errors.New("failed")
+		g.Go(func(context.Context) error { return expectedErr })
+		g.Go(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		assert.Equal(t, expectedErr, g.Wait())
+		assert.Equal(t, expectedErr, context.Cause(ctx))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		g, _ := async.NewGroup(context.Background(), async.WithLimit(2))
+
+		var running, peak atomic.Int32
+		for i := 0; i < 20; i++ {
+			g.Go(func(context.Context) error {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				running.Add(-1)
+				return nil
+			})
+		}
+
+		require.NoError(t, g.Wait())
+		assert.LessOrEqual(t, peak.Load(), int32(2))
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		g, _ := async.NewGroup(context.Background())
+		g.Go(func(context.Context) error { panic("boom") })
+
+		var panicErr *async.PanicError
+		require.ErrorAs(t, g.Wait(), &panicErr)
+		assert.Equal(t, "boom", panicErr.Value)
+	})
+
+	t.Run("parent ctx", func(t *testing.T) {
+		parent, cancel := context.WithCancel(context.Background())
+		g, _ := async.NewGroup(parent)
+
+		g.Go(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		cancel()
+
+		assert.ErrorIs(t, g.Wait(), context.Canceled)
+	})
+}
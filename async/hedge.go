@@ -0,0 +1,114 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lif0/pkg/errx"
+)
+
+// Hedged is the result of Hedge: the value of the winning attempt and its number, starting at 1.
+type Hedged[T any] struct {
+	Value   T
+	Attempt int
+}
+
+// Hedge calls fn and, if it has not succeeded within delay, calls it again in parallel,
+// up to maxAttempts calls in total, and returns a Future for the first successful result.
+// It cuts tail latency against replicated backends: a slow replica no longer delays the answer.
+// It accepts WithClock.
+//
+// Once an attempt succeeds, the context passed to the others is canceled. An attempt that fails
+// starts the next one at once instead of after delay. If every attempt fails, the Future is
+// settled with an errx.MultiError that holds the error of each attempt, prefixed with its number.
+// If ctx is done first, ctx.Err() is added to those errors. A maxAttempts below 1 is treated as 1.
+// A panic in fn is recovered and handled as an attempt that failed with a *PanicError.
+//
+// Example usage:
+//
+//	res, err := async.Hedge(ctx, 50*time.Millisecond, 3, func(ctx context.Context) (*Profile, error) {
+//		return replicas.Next().GetProfile(ctx, id)
+//	}).Result()
+//	if err == nil {
+//		hedgeWins.WithLabelValues(strconv.Itoa(res.Attempt)).Inc()
+//	}
+func Hedge[T any](ctx context.Context, delay time.Duration, maxAttempts int, fn func(ctx context.Context) (T, error), opts ...ClockOption) *Future[Hedged[T]] {
+	clock := SystemClock()
+	for _, opt := range opts {
+		if opt.clock != nil {
+			clock = opt.clock
+		}
+	}
+
+	s := newState[Hedged[T]]()
+
+	go func() {
+		s.settle(hedge(ctx, delay, max(maxAttempts, 1), fn, clock))
+	}()
+
+	return &Future[Hedged[T]]{state: s}
+}
+
+func hedge[T any](ctx context.Context, delay time.Duration, maxAttempts int, fn func(ctx context.Context) (T, error), clock Clock) (Hedged[T], error) {
+	type attempt struct {
+		number int
+		value  T
+		err    error
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // stops the attempts still running
+
+	var (
+		zero     Hedged[T]
+		errs     errx.MultiError
+		started  int
+		finished int
+		results  = make(chan attempt, maxAttempts)
+		timer    = clock.NewTimer(delay)
+	)
+
+	defer timer.Stop()
+
+	start := func() {
+		started++
+		number := started
+
+		go func() {
+			v, err := withRecover(func() (T, error) { return fn(ctx) })()
+			results <- attempt{number: number, value: v, err: err}
+		}()
+
+		timer.Reset(delay)
+	}
+
+	start()
+
+	for {
+		select {
+		case r := <-results:
+			finished++
+			if r.err == nil {
+				return Hedged[T]{Value: r.value, Attempt: r.number}, nil
+			}
+
+			errs.Append(fmt.Errorf("attempt %d: %w", r.number, r.err))
+
+			if started < maxAttempts {
+				start()
+			} else if finished == started {
+				return zero, errs
+			}
+
+		case <-timer.C():
+			if started < maxAttempts {
+				start()
+			}
+
+		case <-ctx.Done():
+			errs.Append(ctx.Err())
+			return zero, errs
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+	"github.com/lif0/pkg/errx"
+)
+
+func TestHedge(t *testing.T) {
+	t.Run("first attempt wins", func(t *testing.T) {
+		var calls atomic.Int32
+		res, err := async.Hedge(context.Background(), time.Hour, 3, func(ctx context.Context) (string, error) {
+			calls.Add(1)
+			return "fast", nil
+		}).Result()
+
+		require.NoError(t, err)
+		assert.Equal(t, async.Hedged[string]{Value: "fast", Attempt: 1}, res)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("hedged attempt wins and the loser is canceled", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		var calls atomic.Int32
+		loserStarted, loserCanceled := make(chan struct{}), make(chan struct{})
+
+		future := async.Hedge(context.Background(), time.Millisecond*50, 3, func(ctx context.Context) (string, error) {
+			if calls.Add(1) == 1 {
+				close(loserStarted)
+				<-ctx.Done()
+				close(loserCanceled)
+				return "", ctx.Err()
+			}
+			return "hedged", nil
+		}, async.WithClock(clock))
+
+		<-loserStarted
+		clock.WaitForTimers(1)
+		clock.Advance(time.Millisecond * 50)
+
+		res, err := future.Result()
+		require.NoError(t, err)
+		assert.Equal(t, async.Hedged[string]{Value: "hedged", Attempt: 2}, res)
+
+		select {
+		case <-loserCanceled:
+		case <-time.After(time.Second):
+			t.Fatal("the losing attempt was not canceled")
+		}
+	})
+
+	t.Run("failure starts the next attempt at once", func(t *testing.T) {
+		var calls atomic.Int32
+		res, err := async.Hedge(context.Background(), time.Hour, 3, func(ctx context.Context) (int, error) {
+			if n := calls.Add(1); n < 3 {
+				return 0, errors.New("unavailable")
+			}
+			return 42, nil
+		}).Result()
+
+		require.NoError(t, err)
+		assert.Equal(t, async.Hedged[int]{Value: 42, Attempt: 3}, res)
+	})
+
+	t.Run("all attempts fail", func(t *testing.T) {
+		fail := errors.New("unavailable")
+		var calls atomic.Int32
+		_, err := async.Hedge(context.Background(), time.Hour, 3, func(ctx context.Context) (int, error) {
+			calls.Add(1)
+			return 0, fail
+		}).Result()
+
+		var errs errx.MultiError
+		require.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 3)
+		assert.ErrorIs(t, err, fail)
+		assert.EqualError(t, errs[0], "attempt 1: unavailable")
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		started := make(chan struct{}, 10)
+		release := make(chan struct{})
+
+		future := async.Hedge(context.Background(), time.Millisecond, 2, func(ctx context.Context) (int, error) {
+			started <- struct{}{}
+			<-release
+			return 0, nil
+		}, async.WithClock(clock))
+
+		clock.WaitForTimers(1)
+		clock.Advance(time.Millisecond)
+		clock.WaitForTimers(1)
+		clock.Advance(time.Hour)
+
+		<-started
+		<-started
+		close(release)
+
+		_, err := future.Result()
+		require.NoError(t, err)
+		assert.Empty(t, started, "no attempt may start after maxAttempts")
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		future := async.Hedge(ctx, time.Hour, 3, func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+
+		cancel()
+		_, err := future.Result()
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		_, err := async.Hedge(context.Background(), time.Hour, 1, func(ctx context.Context) (int, error) {
+			panic("boom")
+		}).Result()
+
+		var panicErr *async.PanicError
+		assert.ErrorAs(t, err, &panicErr)
+	})
+}
@@ -0,0 +1,146 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LazyOption configures a Lazy. It is either WithTTL or a ClockOption.
+type LazyOption interface {
+	applyLazy(o *lazyOptions)
+}
+
+type lazyOptionFunc func(*lazyOptions)
+
+func (f lazyOptionFunc) applyLazy(o *lazyOptions) { f(o) }
+
+type lazyOptions struct {
+	ttl   time.Duration
+	clock Clock
+}
+
+func (c ClockOption) applyLazy(o *lazyOptions) {
+	if c.clock != nil {
+		o.clock = c.clock
+	}
+}
+
+// WithTTL makes the value of a Lazy expire ttl after it was computed;
+// the next read after that computes it again. The default is 0, the value never expires.
+func WithTTL(ttl time.Duration) LazyOption {
+	return lazyOptionFunc(func(o *lazyOptions) {
+		o.ttl = max(ttl, 0)
+	})
+}
+
+// Lazy is a memoized value that is computed on first use.
+// Unlike NewFutureAction, which starts its action at once, Lazy does not call its function
+// until the value is read for the first time; every later read returns the cached value.
+//
+// Concurrent first reads share one computation. A failed computation is not cached:
+// the next read tries again. A panic in the function is recovered and returned as a *PanicError.
+// Invalidate drops the cached value, and WithTTL makes it expire on its own.
+//
+// All methods are safe for concurrent use by multiple goroutines.
+//
+// Example usage:
+//
+//	client := async.NewLazy(func() (*Client, error) {
+//		return NewClient(loadConfig())
+//	})
+//
+//	c, err := client.Result() // the client is created here, once
+type Lazy[T any] struct {
+	fn    func() (T, error)
+	ttl   time.Duration
+	clock Clock
+
+	mu      sync.Mutex
+	current *Future[T]
+	expires time.Time
+}
+
+// NewLazy returns a Lazy for the value computed by fn. It accepts WithTTL and WithClock.
+func NewLazy[T any](fn func() (T, error), opts ...LazyOption) *Lazy[T] {
+	o := lazyOptions{clock: SystemClock()}
+	for _, opt := range opts {
+		opt.applyLazy(&o)
+	}
+
+	return &Lazy[T]{
+		fn:    fn,
+		ttl:   o.ttl,
+		clock: o.clock,
+	}
+}
+
+// Get returns the value, computing it first if needed. On error it returns the zero value.
+func (l *Lazy[T]) Get() T {
+	return l.Future().Get()
+}
+
+// Result returns the value and the error, computing the value first if needed.
+func (l *Lazy[T]) Result() (T, error) {
+	return l.Future().Result()
+}
+
+// GetContext returns the value and the error, computing the value first if needed,
+// and gives up waiting once ctx is done. Giving up does not stop the computation,
+// whose result is still cached for later reads.
+func (l *Lazy[T]) GetContext(ctx context.Context) (T, error) {
+	return l.Future().GetContext(ctx)
+}
+
+// Future returns the Future of the current computation, starting one if there is no
+// cached value, the cached value has expired, or the last computation failed.
+func (l *Lazy[T]) Future() *Future[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.current != nil && l.valid() {
+		return l.current
+	}
+
+	f := &Future[T]{state: newState[T]()}
+	l.current = f
+
+	go func() {
+		v, err := withRecover(l.fn)()
+
+		l.mu.Lock()
+		if l.current == f {
+			l.expires = l.clock.Now().Add(l.ttl)
+		}
+		l.mu.Unlock()
+
+		f.state.settle(v, err)
+	}()
+
+	return f
+}
+
+// Invalidate drops the cached value, so that the next read computes it again.
+// Readers already waiting on a computation in progress still receive its result.
+func (l *Lazy[T]) Invalidate() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.current = nil
+}
+
+// valid reports whether l.current may be returned: it is in progress, or it succeeded and has not expired.
+// l.mu must be held.
+func (l *Lazy[T]) valid() bool {
+	select {
+	case <-l.current.Done():
+	default:
+		return true // in progress
+	}
+
+	if l.current.state.err != nil {
+		return false
+	}
+
+	return l.ttl <= 0 || l.clock.Now().Before(l.expires)
+}
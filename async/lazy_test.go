@@ -0,0 +1,138 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+)
+
+func TestLazy(t *testing.T) {
+	t.Run("computed on first use, once", func(t *testing.T) {
+		var calls atomic.Int32
+		lazy := async.NewLazy(func() (int, error) {
+			return int(calls.Add(1)), nil
+		})
+
+		time.Sleep(time.Millisecond * 10)
+		assert.Equal(t, int32(0), calls.Load(), "Lazy must not compute before the first read")
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.Equal(t, 1, lazy.Get())
+			}()
+		}
+		wg.Wait()
+
+		v, err := lazy.Result()
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		var calls atomic.Int32
+		lazy := async.NewLazy(func() (string, error) {
+			if calls.Add(1) == 1 {
+				return "", errors.New("failed")
+			}
+			return "value", nil
+		})
+
+		_, err := lazy.Result()
+		require.Error(t, err)
+
+		v, err := lazy.Result()
+		require.NoError(t, err)
+		assert.Equal(t, "value", v)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		var calls atomic.Int32
+		lazy := async.NewLazy(func() (int, error) {
+			return int(calls.Add(1)), nil
+		})
+
+		assert.Equal(t, 1, lazy.Get())
+		assert.Equal(t, 1, lazy.Get())
+
+		lazy.Invalidate()
+		assert.Equal(t, 2, lazy.Get())
+	})
+
+	t.Run("invalidate during computation", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		var calls atomic.Int32
+		lazy := async.NewLazy(func() (int, error) {
+			if calls.Add(1) == 1 {
+				close(started)
+				<-release
+			}
+			return int(calls.Load()), nil
+		})
+
+		first := lazy.Future()
+		<-started
+		lazy.Invalidate()
+		assert.Equal(t, 2, lazy.Get())
+
+		close(release)
+		assert.Equal(t, 2, first.Get())
+		assert.Equal(t, 2, lazy.Get(), "the stale computation must not replace the current one")
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		var calls atomic.Int32
+		lazy := async.NewLazy(func() (int, error) {
+			return int(calls.Add(1)), nil
+		}, async.WithTTL(time.Minute), async.WithClock(clock))
+
+		assert.Equal(t, 1, lazy.Get())
+
+		clock.Advance(time.Second * 59)
+		assert.Equal(t, 1, lazy.Get())
+
+		clock.Advance(time.Second)
+		assert.Equal(t, 2, lazy.Get())
+	})
+
+	t.Run("get context", func(t *testing.T) {
+		release := make(chan struct{})
+		lazy := async.NewLazy(func() (int, error) {
+			<-release
+			return 42, nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := lazy.GetContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+		v, err := lazy.GetContext(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 42, v)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		lazy := async.NewLazy(func() (int, error) { panic("boom") })
+
+		var panicErr *async.PanicError
+		_, err := lazy.Result()
+		assert.ErrorAs(t, err, &panicErr)
+	})
+}
@@ -1,8 +1,14 @@
 package async
 
-import "sync/atomic"
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 // PromiseError is a Promise specialized for error propagation.
+// To deliver a value together with an error, use Promise.Reject and Future.Result instead.
 type PromiseError = Promise[error]
 
 // FutureError is a Future specialized for error propagation.
@@ -10,9 +16,14 @@ type FutureError = Future[error]
 
 // Promise represents a writable, single-assignment container for a future value.
 // It allows setting a value exactly once. Attempting to set the value more than once is ignored.
-// The internal channel is buffered to hold one value and is closed after setting.
+// Once set, the value is stored and every Future of the Promise observes it.
 // Synchronization is handled via atomic operations for thread safety.
 //
+// A Promise is settled either with a value (Set or Resolve) or with an error (Reject).
+// Future.Result and Future.GetContext report the error of a rejected Promise.
+//
+// A Promise must be created with NewPromise; copies of it share the same value.
+//
 // Example usage:
 //
 //	func main() {
@@ -27,52 +38,234 @@ type FutureError = Future[error]
 //	    fmt.Println(value) // Output: Cake
 //	}
 type Promise[T any] struct {
-	result   chan T
-	promised atomic.Bool
+	state *state[T]
 }
 
 // Future represents a read-only view of a promised value.
 // It provides a way to retrieve the value asynchronously, blocking if necessary.
+//
+// Any number of goroutines may wait on the same Future, and Get may be called
+// repeatedly: every call returns the same settled value.
 type Future[T any] struct {
-	result <-chan T
+	state *state[T]
+}
+
+// Awaitable is a settled-once result that can be waited on.
+// It is implemented by *Future and *FutureAction, and is accepted by the
+// combinators of this package, such as Then and Catch.
+type Awaitable[T any] interface {
+	// Done returns a channel that is closed once the result is settled.
+	Done() <-chan struct{}
+	// Result blocks until the result is settled and returns the value and the error.
+	Result() (T, error)
+}
+
+var (
+	_ Awaitable[any] = (*Future[any])(nil)
+	_ Awaitable[any] = (*FutureAction[any])(nil)
+)
+
+// state is the single-assignment result shared by a Promise and its Futures.
+// value and err are written once, before done is closed, and are read only after done is closed.
+type state[T any] struct {
+	done    chan struct{}
+	settled atomic.Bool
+	value   T
+	err     error
+
+	mu        sync.Mutex // guards callbacks and orders their registration against close(done)
+	callbacks []func(T, error)
+}
+
+func newState[T any]() *state[T] {
+	return &state[T]{
+		done: make(chan struct{}),
+	}
+}
+
+// settle stores the result and releases all waiters.
+// It reports whether this call settled the state; later calls are ignored.
+func (s *state[T]) settle(value T, err error) bool {
+	if !s.settled.CompareAndSwap(false, true) {
+		return false
+	}
+
+	s.value = value
+	s.err = err
+
+	s.mu.Lock()
+	close(s.done)
+	callbacks := s.callbacks
+	s.callbacks = nil
+	s.mu.Unlock()
+
+	if len(callbacks) > 0 {
+		go func() {
+			for _, cb := range callbacks {
+				cb(value, err)
+			}
+		}()
+	}
+
+	return true
+}
+
+// onSettle registers cb to be called with the result once the state is settled.
+// If the state is already settled, cb is called at once in the calling goroutine.
+func (s *state[T]) onSettle(cb func(T, error)) {
+	s.mu.Lock()
+
+	select {
+	case <-s.done:
+		s.mu.Unlock()
+		cb(s.value, s.err)
+	default:
+		s.callbacks = append(s.callbacks, cb)
+		s.mu.Unlock()
+	}
 }
 
 // NewPromise creates and returns a new Promise.
-// The internal channel is buffered with capacity 1 to hold the future value.
 func NewPromise[T any]() Promise[T] {
 	return Promise[T]{
-		result: make(chan T, 1),
+		state: newState[T](),
 	}
 }
 
 // Set assigns the value to the Promise.
 // This can be called only once; subsequent calls are ignored.
-// After setting, the value is sent to the channel, and the channel is closed.
+// After setting, all Futures of the Promise are released.
 func (p *Promise[T]) Set(value T) {
-	if !p.promised.CompareAndSwap(false, true) {
-		return
-	}
+	p.state.settle(value, nil)
+}
 
-	p.result <- value
-	close(p.result)
+// Resolve settles the Promise with the value; it is equivalent to Set.
+// This can be called only once; subsequent calls to Set, Resolve or Reject are ignored.
+func (p *Promise[T]) Resolve(value T) {
+	p.Set(value)
+}
+
+// Reject settles the Promise with the error instead of a value.
+// This can be called only once; subsequent calls to Set, Resolve or Reject are ignored.
+// After rejecting, Get returns the zero value and Result returns the error.
+func (p *Promise[T]) Reject(err error) {
+	var zero T
+	p.state.settle(zero, err)
 }
 
 // GetFuture returns a Future associated with this Promise.
 // The Future can be used to retrieve the value once it's set.
+// GetFuture may be called any number of times; all returned Futures observe the same value.
 func (p *Promise[T]) GetFuture() *Future[T] {
-	return NewFuture[T](p.result)
+	return &Future[T]{
+		state: p.state,
+	}
 }
 
 // NewFuture creates a new Future from a given receive-only channel.
 // This allows wrapping an existing channel as a Future.
+// A background goroutine receives exactly one value from the channel and settles the Future with it;
+// if the channel is closed without a value, the Future is settled with the zero value.
 func NewFuture[T any](result <-chan T) *Future[T] {
+	s := newState[T]()
+
+	go func() {
+		s.settle(<-result, nil)
+	}()
+
 	return &Future[T]{
-		result: result,
+		state: s,
 	}
 }
 
 // Get retrieves the value from the Future, blocking until it's available.
-// If the channel is closed without a value (though not typical in this pattern), it returns the zero value.
+// If the Promise was rejected, it returns the zero value.
 func (f *Future[T]) Get() T {
-	return <-f.result
+	<-f.state.done
+	return f.state.value
+}
+
+// Result retrieves the value and the error from the Future, blocking until it's settled.
+// For a rejected Promise, it returns the zero value and the rejection error.
+func (f *Future[T]) Result() (T, error) {
+	<-f.state.done
+	return f.state.value, f.state.err
+}
+
+// Done returns a channel that is closed once the Future is settled.
+// It allows waiting on a Future inside a select statement.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.state.done
+}
+
+// OnComplete registers fn to be called with the value and the error of the Future once it is settled.
+//
+// Callbacks registered before the Future is settled are called one after another,
+// in the order of registration, in a single goroutine started when the Future is settled.
+// Callbacks registered after the Future is settled are called at once, in the goroutine
+// that registers them, before OnComplete returns. Done is always closed before any callback is called.
+// A slow callback delays the callbacks registered after it, but never the code that settles the Future.
+func (f *Future[T]) OnComplete(fn func(T, error)) {
+	f.state.onSettle(fn)
+}
+
+// OnSuccess registers fn to be called with the value of the Future once it is settled without an error.
+// It follows the ordering rules of OnComplete.
+func (f *Future[T]) OnSuccess(fn func(T)) {
+	f.state.onSettle(func(v T, err error) {
+		if err == nil {
+			fn(v)
+		}
+	})
+}
+
+// OnError registers fn to be called with the error of the Future once it is settled with an error.
+// It follows the ordering rules of OnComplete.
+func (f *Future[T]) OnError(fn func(error)) {
+	f.state.onSettle(func(_ T, err error) {
+		if err != nil {
+			fn(err)
+		}
+	})
+}
+
+// GetContext retrieves the value from the Future, blocking until it's available
+// or ctx is done. If ctx is done first, it returns the zero value and ctx.Err().
+// For a rejected Promise, it returns the zero value and the rejection error.
+func (f *Future[T]) GetContext(ctx context.Context) (T, error) {
+	select {
+	case <-f.state.done:
+		return f.state.value, f.state.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// GetTimeout retrieves the value from the Future, blocking for at most d.
+// If the value is not available in time, it returns the zero value and ErrTimeout.
+// For a rejected Promise, it returns the zero value and the rejection error.
+func (f *Future[T]) GetTimeout(d time.Duration) (T, error) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-f.state.done:
+		return f.state.value, f.state.err
+	case <-timer.C:
+		var zero T
+		return zero, ErrTimeout
+	}
+}
+
+// TryGet retrieves the value from the Future without blocking.
+// The second return value reports whether the Future is settled.
+func (f *Future[T]) TryGet() (T, bool) {
+	select {
+	case <-f.state.done:
+		return f.state.value, true
+	default:
+		var zero T
+		return zero, false
+	}
 }
@@ -1,6 +1,7 @@
 package async_test
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"testing"
@@ -124,11 +125,244 @@ func TestConcurrentSet(t *testing.T) {
 		t.Errorf("ConcurrentSet: got unexpected value '%s'", value)
 	}
 
-	// Since we can't check if channel has only one value directly, we rely on the fact that Get returns one,
-	// and multiple Sets are ignored after the first.
-	// Additional check: try to read again; should be zero since closed.
-	zero := f.Get()
-	if zero != "" {
-		t.Errorf("ConcurrentSet: expected zero value on second Get, got '%s'", zero)
+	// Multiple Sets are ignored after the first, and every Get observes the same value.
+	again := f.Get()
+	if again != value {
+		t.Errorf("ConcurrentSet: expected '%s' on second Get, got '%s'", value, again)
+	}
+}
+
+func TestFutureMultipleReaders(t *testing.T) {
+	p := async.NewPromise[string]()
+	n := 10
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	results := make([]string, n)
+
+	for i := 0; i < n; i++ {
+		f := p.GetFuture()
+		go func() {
+			defer wg.Done()
+			results[i] = f.Get()
+		}()
+	}
+
+	p.Set("shared")
+	wg.Wait()
+
+	for i, value := range results {
+		if value != "shared" {
+			t.Errorf("MultipleReaders: reader %d expected 'shared', got '%s'", i, value)
+		}
+	}
+}
+
+func TestFutureDone(t *testing.T) {
+	p := async.NewPromise[int]()
+	f := p.GetFuture()
+
+	select {
+	case <-f.Done():
+		t.Error("Done: expected open channel before Set")
+	default:
+	}
+
+	p.Set(1)
+
+	select {
+	case <-f.Done():
+	case <-time.After(time.Second):
+		t.Error("Done: expected closed channel after Set")
+	}
+
+	if value, ok := f.TryGet(); !ok || value != 1 {
+		t.Errorf("Done: expected value 1, got %d (ok: %v)", value, ok)
+	}
+}
+
+func TestFutureGetContext(t *testing.T) {
+	p := async.NewPromise[string]()
+	f := p.GetFuture()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	value, err := f.GetContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetContext: expected deadline exceeded, got '%v'", err)
+	}
+	if value != "" {
+		t.Errorf("GetContext: expected zero value, got '%s'", value)
+	}
+
+	p.Set("test")
+	value, err = f.GetContext(context.Background())
+	if err != nil || value != "test" {
+		t.Errorf("GetContext: expected value 'test', got '%s' (err: %v)", value, err)
+	}
+}
+
+func TestFutureGetTimeout(t *testing.T) {
+	p := async.NewPromise[string]()
+	f := p.GetFuture()
+
+	value, err := f.GetTimeout(time.Millisecond * 10)
+	if !errors.Is(err, async.ErrTimeout) {
+		t.Errorf("GetTimeout: expected ErrTimeout, got '%v'", err)
+	}
+	if value != "" {
+		t.Errorf("GetTimeout: expected zero value, got '%s'", value)
+	}
+
+	p.Set("test")
+	value, err = f.GetTimeout(time.Second)
+	if err != nil || value != "test" {
+		t.Errorf("GetTimeout: expected value 'test', got '%s' (err: %v)", value, err)
+	}
+}
+
+func TestFutureTryGet(t *testing.T) {
+	p := async.NewPromise[string]()
+	f := p.GetFuture()
+
+	if value, ok := f.TryGet(); ok {
+		t.Errorf("TryGet: expected no value, got '%s'", value)
+	}
+
+	p.Set("test")
+	if value, ok := f.TryGet(); !ok || value != "test" {
+		t.Errorf("TryGet: expected value 'test', got '%s' (ok: %v)", value, ok)
+	}
+}
+
+func TestPromiseResolve(t *testing.T) {
+	p := async.NewPromise[int]()
+	p.Resolve(42)
+	p.Reject(errors.New("ignored")) // Should be ignored.
+
+	value, err := p.GetFuture().Result()
+	if err != nil || value != 42 {
+		t.Errorf("Resolve: expected value 42, got %d (err: %v)", value, err)
+	}
+}
+
+func TestPromiseReject(t *testing.T) {
+	p := async.NewPromise[int]()
+	expectedErr := errors.New("test error")
+	go func() {
+		time.Sleep(time.Millisecond * 50)
+		p.Reject(expectedErr)
+		p.Resolve(42) // Should be ignored.
+	}()
+
+	value, err := p.GetFuture().Result()
+	if !errors.Is(err, expectedErr) {
+		t.Errorf("Reject: expected error '%v', got '%v'", expectedErr, err)
+	}
+	if value != 0 {
+		t.Errorf("Reject: expected zero value, got %d", value)
+	}
+}
+
+func TestPromiseRejectGetContext(t *testing.T) {
+	p := async.NewPromise[int]()
+	expectedErr := errors.New("test error")
+	p.Reject(expectedErr)
+
+	if _, err := p.GetFuture().GetContext(context.Background()); !errors.Is(err, expectedErr) {
+		t.Errorf("GetContext: expected error '%v', got '%v'", expectedErr, err)
+	}
+}
+
+func TestNewFutureResult(t *testing.T) {
+	ch := make(chan string, 1)
+	ch <- "test"
+	close(ch)
+
+	value, err := async.NewFuture[string](ch).Result()
+	if err != nil || value != "test" {
+		t.Errorf("Result: expected value 'test', got '%s' (err: %v)", value, err)
+	}
+}
+
+func TestFutureOnComplete(t *testing.T) {
+	p := async.NewPromise[int]()
+	f := p.GetFuture()
+
+	results := make(chan int, 3)
+	f.OnComplete(func(v int, err error) {
+		if err != nil {
+			t.Errorf("OnComplete: unexpected error '%v'", err)
+		}
+		results <- v
+	})
+	f.OnComplete(func(v int, _ error) { results <- v * 10 })
+
+	p.Set(1)
+
+	if first, second := <-results, <-results; first != 1 || second != 10 {
+		t.Errorf("OnComplete: expected callbacks in registration order (1, 10), got (%d, %d)", first, second)
+	}
+
+	// Registered after settlement: called at once, before OnComplete returns.
+	f.OnComplete(func(v int, _ error) { results <- v * 100 })
+	select {
+	case v := <-results:
+		if v != 100 {
+			t.Errorf("OnComplete: expected 100, got %d", v)
+		}
+	default:
+		t.Error("OnComplete: callback registered after settlement must be called at once")
+	}
+}
+
+func TestFutureOnCompleteDoneIsClosed(t *testing.T) {
+	p := async.NewPromise[int]()
+	f := p.GetFuture()
+
+	closed := make(chan bool, 1)
+	f.OnComplete(func(int, error) {
+		select {
+		case <-f.Done():
+			closed <- true
+		default:
+			closed <- false
+		}
+	})
+
+	p.Set(1)
+	if !<-closed {
+		t.Error("OnComplete: Done must be closed before callbacks are called")
+	}
+}
+
+func TestFutureOnSuccessOnError(t *testing.T) {
+	expectedErr := errors.New("test error")
+
+	ok := async.NewPromise[string]()
+	failed := async.NewPromise[string]()
+
+	successes := make(chan string, 2)
+	failures := make(chan error, 2)
+
+	for _, f := range []*async.Future[string]{ok.GetFuture(), failed.GetFuture()} {
+		f.OnSuccess(func(v string) { successes <- v })
+		f.OnError(func(err error) { failures <- err })
+	}
+
+	ok.Resolve("value")
+	failed.Reject(expectedErr)
+
+	if v := <-successes; v != "value" {
+		t.Errorf("OnSuccess: expected 'value', got '%s'", v)
+	}
+	if err := <-failures; !errors.Is(err, expectedErr) {
+		t.Errorf("OnError: expected '%v', got '%v'", expectedErr, err)
+	}
+
+	time.Sleep(time.Millisecond * 10)
+	if len(successes) != 0 || len(failures) != 0 {
+		t.Error("OnSuccess/OnError: callbacks called for the wrong outcome")
 	}
 }
@@ -0,0 +1,127 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lif0/pkg/errx"
+)
+
+// RetryPolicy configures Retry. The zero value retries every error without limit,
+// with an exponential backoff from 100ms up to 10s.
+type RetryPolicy struct {
+	// Backoff computes the delay between attempts. If nil, the delay is 100ms after the first
+	// attempt and doubles on every attempt up to 10s. Use ConstantBackoff(0) to retry immediately.
+	Backoff Backoff
+	// MaxAttempts limits the number of attempts, including the first one. If 0, there is no limit.
+	MaxAttempts int
+	// MaxElapsed stops retrying when the next attempt would start later than MaxElapsed
+	// after the first one. If 0, there is no limit.
+	MaxElapsed time.Duration
+	// Retryable reports whether an error is worth another attempt. If nil, every error is.
+	Retryable func(err error) bool
+	// Clock is the source of time for the delays and MaxElapsed. If nil, SystemClock is used.
+	// Pass a FakeClock to control time in tests.
+	Clock Clock
+}
+
+const (
+	defaultRetryBase     = 100 * time.Millisecond
+	defaultRetryMaxDelay = 10 * time.Second
+)
+
+// Retry calls fn until it succeeds, following policy, and returns a Future for the result.
+// fn runs in a goroutine started by Retry and receives ctx.
+//
+// If fn never succeeds, the Future is settled with an errx.MultiError that holds the error of
+// every attempt, each prefixed with its attempt number, so the whole history is visible.
+// Retrying stops when an error is not retryable, when MaxAttempts or MaxElapsed is reached,
+// or when ctx is done; in the last case ctx.Err() is added to the history.
+// A panic in fn is recovered and handled as an attempt that failed with a *PanicError.
+//
+// Example usage:
+//
+//	policy := async.RetryPolicy{
+//		Backoff:     async.ExponentialBackoff(100*time.Millisecond, 5*time.Second),
+//		MaxAttempts: 5,
+//		Retryable:   func(err error) bool { return !errors.Is(err, ErrNotFound) },
+//	}
+//	user, err := async.Retry(ctx, policy, func(ctx context.Context) (User, error) {
+//		return client.GetUser(ctx, id)
+//	}).Result()
+func Retry[T any](ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) (T, error)) *Future[T] {
+	s := newState[T]()
+
+	go func() {
+		s.settle(retry(ctx, policy, withRecover(func() (T, error) { return fn(ctx) })))
+	}()
+
+	return &Future[T]{state: s}
+}
+
+func retry[T any](ctx context.Context, policy RetryPolicy, fn func() (T, error)) (T, error) {
+	if policy.Backoff == nil {
+		policy.Backoff = ExponentialBackoff(defaultRetryBase, defaultRetryMaxDelay)
+	}
+	if policy.Clock == nil {
+		policy.Clock = SystemClock()
+	}
+
+	var (
+		zero  T
+		errs  errx.MultiError
+		delay time.Duration
+		start = policy.Clock.Now()
+	)
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			errs.Append(err)
+			return zero, errs
+		}
+
+		v, err := fn()
+		if err == nil {
+			return v, nil
+		}
+
+		errs.Append(fmt.Errorf("attempt %d: %w", attempt, err))
+
+		if policy.Retryable != nil && !policy.Retryable(err) {
+			return zero, errs
+		}
+
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return zero, errs
+		}
+
+		delay = policy.Backoff(attempt, delay)
+
+		if policy.MaxElapsed > 0 && policy.Clock.Now().Sub(start)+delay > policy.MaxElapsed {
+			return zero, errs
+		}
+
+		if err := sleep(ctx, policy.Clock, delay); err != nil {
+			errs.Append(err)
+			return zero, errs
+		}
+	}
+}
+
+// sleep waits for d on clock or until ctx is done, in which case it returns ctx.Err().
+func sleep(ctx context.Context, clock Clock, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
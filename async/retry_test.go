@@ -0,0 +1,156 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+	"github.com/lif0/pkg/errx"
+)
+
+func TestRetry(t *testing.T) {
+	t.Run("succeeds after failures", func(t *testing.T) {
+		calls := 0
+		policy := async.RetryPolicy{Backoff: async.ConstantBackoff(time.Millisecond)}
+
+		v, err := async.Retry(context.Background(), policy, func(context.Context) (int, error) {
+			calls++
+			if calls < 3 {
+				return 0, errors.New("unavailable")
+			}
+			return 42, nil
+		}).Result()
+
+		require.NoError(t, err)
+		assert.Equal(t, 42, v)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("max attempts keeps history", func(t *testing.T) {
+		calls := 0
+		policy := async.RetryPolicy{MaxAttempts: 3}
+
+		_, err := async.Retry(context.Background(), policy, func(context.Context) (int, error) {
+			calls++
+			return 0, errors.New("unavailable")
+		}).Result()
+
+		var multiErr errx.MultiError
+		require.ErrorAs(t, err, &multiErr)
+		assert.Equal(t, 3, calls)
+		assert.Len(t, multiErr, 3)
+		assert.EqualError(t, multiErr[0], "attempt 1: unavailable")
+		assert.EqualError(t, multiErr[2], "attempt 3: unavailable")
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		permanent := errors.New("not found")
+		calls := 0
+		policy := async.RetryPolicy{
+			Retryable: func(err error) bool { return !errors.Is(err, permanent) },
+		}
+
+		_, err := async.Retry(context.Background(), policy, func(context.Context) (int, error) {
+			calls++
+			if calls == 2 {
+				return 0, permanent
+			}
+			return 0, errors.New("unavailable")
+		}).Result()
+
+		assert.ErrorIs(t, err, permanent)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("max elapsed", func(t *testing.T) {
+		clock := async.NewFakeClock(time.Now())
+		var calls atomic.Int32
+		policy := async.RetryPolicy{
+			Backoff:    async.ConstantBackoff(time.Minute),
+			MaxElapsed: time.Minute + time.Second*30,
+			Clock:      clock,
+		}
+
+		future := async.Retry(context.Background(), policy, func(context.Context) (int, error) {
+			calls.Add(1)
+			return 0, errors.New("unavailable")
+		})
+
+		clock.WaitForTimers(1)
+		clock.Advance(time.Minute) // the second attempt starts a minute after the first
+
+		_, err := future.Result() // a third one would start two minutes after the first
+		require.Error(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("default backoff", func(t *testing.T) {
+		clock := async.NewFakeClock(time.Now())
+		var calls atomic.Int32
+
+		future := async.Retry(context.Background(), async.RetryPolicy{Clock: clock}, func(context.Context) (int, error) {
+			if calls.Add(1) < 3 {
+				return 0, errors.New("unavailable")
+			}
+			return 42, nil
+		})
+
+		clock.WaitForTimers(1)
+		clock.Advance(time.Millisecond * 99)
+		assert.Equal(t, int32(1), calls.Load(), "the zero policy must not retry immediately")
+
+		clock.Advance(time.Millisecond)
+		clock.WaitForTimers(1)
+		clock.Advance(time.Millisecond * 200) // the delay doubles
+
+		v, err := future.Result()
+		require.NoError(t, err)
+		assert.Equal(t, 42, v)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("ctx done while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+		defer cancel()
+
+		policy := async.RetryPolicy{Backoff: async.ConstantBackoff(time.Hour)}
+
+		_, err := async.Retry(ctx, policy, func(context.Context) (int, error) {
+			return 0, errors.New("unavailable")
+		}).Result()
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("ctx done before attempt", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := async.Retry(ctx, async.RetryPolicy{}, func(context.Context) (int, error) {
+			t.Error("fn must not be called")
+			return 0, nil
+		}).Result()
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		calls := 0
+		policy := async.RetryPolicy{MaxAttempts: 2}
+
+		_, err := async.Retry(context.Background(), policy, func(context.Context) (int, error) {
+			calls++
+			panic("boom")
+		}).Result()
+
+		var panicErr *async.PanicError
+		assert.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, 2, calls)
+	})
+}
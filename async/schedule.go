@@ -0,0 +1,193 @@
+package async
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ScheduleOption configures After, At and Every.
+type ScheduleOption interface {
+	applySchedule(o *scheduleOptions)
+}
+
+type scheduleOptionFunc func(*scheduleOptions)
+
+func (f scheduleOptionFunc) applySchedule(o *scheduleOptions) { f(o) }
+
+type scheduleOptions struct {
+	clock        Clock
+	jitter       time.Duration
+	allowOverlap bool
+}
+
+func newScheduleOptions(opts []ScheduleOption) scheduleOptions {
+	o := scheduleOptions{clock: SystemClock()}
+	for _, opt := range opts {
+		opt.applySchedule(&o)
+	}
+
+	return o
+}
+
+// ClockOption sets the Clock used for timing. It is created by WithClock and is accepted
+// by every time-based helper of this package, whatever its option type.
+type ClockOption struct {
+	clock Clock
+}
+
+// WithClock sets the Clock used for timing. The default is SystemClock.
+// Pass a FakeClock to control time in tests.
+func WithClock(clock Clock) ClockOption {
+	return ClockOption{clock: clock}
+}
+
+func (c ClockOption) applySchedule(o *scheduleOptions) {
+	if c.clock != nil {
+		o.clock = c.clock
+	}
+}
+
+// WithJitter adds a random delay in [0, jitter) to every interval of Every,
+// so that many tickers started together do not fire in lockstep.
+func WithJitter(jitter time.Duration) ScheduleOption {
+	return scheduleOptionFunc(func(o *scheduleOptions) {
+		o.jitter = max(jitter, 0)
+	})
+}
+
+// WithOverlap lets Every start a run while the previous one is still in progress.
+// By default a tick that comes while fn is running is skipped.
+func WithOverlap() ScheduleOption {
+	return scheduleOptionFunc(func(o *scheduleOptions) {
+		o.allowOverlap = true
+	})
+}
+
+// After returns a FutureAction settled with the result of fn, which is called once d has elapsed.
+// Cancel stops the timer; if fn has not been called yet, it never is.
+// It accepts WithClock.
+//
+// Example usage:
+//
+//	reminder := async.After(time.Minute, func() string { return "time is up" })
+//	fmt.Println(reminder.Get())
+func After[T any](d time.Duration, fn func() T, opts ...ScheduleOption) *FutureAction[T] {
+	o := newScheduleOptions(opts)
+	timer := o.clock.NewTimer(d)
+
+	return newFutureAction(context.Background(), true, func(ctx context.Context) (T, error) {
+		select {
+		case <-timer.C():
+			return fn(), nil
+		case <-ctx.Done():
+			timer.Stop()
+			var zero T
+			return zero, ctx.Err()
+		}
+	}, nil)
+}
+
+// At returns a FutureAction settled with the result of fn, which is called at t.
+// If t is in the past, fn is called immediately. Cancel stops the timer; if fn has not
+// been called yet, it never is. It accepts WithClock.
+func At[T any](t time.Time, fn func() T, opts ...ScheduleOption) *FutureAction[T] {
+	o := newScheduleOptions(opts)
+	return After(t.Sub(o.clock.Now()), fn, WithClock(o.clock))
+}
+
+// Ticker is the handle of a recurring task started by Every.
+type Ticker struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Every calls fn every interval until Stop is called, and returns the handle of the task.
+// Runs are started on a fixed rate: a run that takes longer than interval does not delay the next tick.
+// Unless WithOverlap is given, a tick that comes while the previous run is still in progress is skipped,
+// so runs never overlap. WithJitter adds a random delay to every interval.
+// It accepts WithClock, WithJitter and WithOverlap.
+//
+// fn runs in its own goroutine and receives a context that is canceled by Stop.
+// Like time.NewTicker, Every panics if interval is not positive.
+//
+// Example usage:
+//
+//	ticker := async.Every(30*time.Second, func(ctx context.Context) {
+//		flushMetrics(ctx)
+//	}, async.WithJitter(time.Second))
+//	defer ticker.Stop()
+func Every(interval time.Duration, fn func(ctx context.Context), opts ...ScheduleOption) *Ticker {
+	if interval <= 0 {
+		panic("async.Every: non-positive interval")
+	}
+
+	o := newScheduleOptions(opts)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	t := &Ticker{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	timer := o.clock.NewTimer(o.interval(interval))
+	go t.run(ctx, timer, interval, fn, o)
+
+	return t
+}
+
+// Stop stops the recurring task and cancels the context of a run in progress.
+// It does not wait for that run to return; use Done for that. Stop may be called
+// multiple times, including from fn.
+func (t *Ticker) Stop() {
+	t.cancel()
+}
+
+// Done returns a channel that is closed once the task is stopped and its last run has returned.
+func (t *Ticker) Done() <-chan struct{} {
+	return t.done
+}
+
+func (t *Ticker) run(ctx context.Context, timer Timer, interval time.Duration, fn func(ctx context.Context), o scheduleOptions) {
+	var (
+		wg      sync.WaitGroup
+		running atomic.Bool
+	)
+
+	defer close(t.done)
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+
+		timer.Reset(o.interval(interval))
+
+		if !o.allowOverlap && !running.CompareAndSwap(false, true) {
+			continue // the previous run is still in progress
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer running.Store(false)
+
+			fn(ctx)
+		}()
+	}
+}
+
+// interval returns d with a random jitter added.
+func (o scheduleOptions) interval(d time.Duration) time.Duration {
+	if o.jitter <= 0 {
+		return d
+	}
+
+	return d + time.Duration(rand.Int64N(int64(o.jitter))) // #nosec G404 -- jitter does not need a cryptographic source
+}
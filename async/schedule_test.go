@@ -0,0 +1,197 @@
+package async_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+)
+
+func TestAfter(t *testing.T) {
+	t.Run("fires after delay", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		future := async.After(time.Minute, func() int { return 42 }, async.WithClock(clock))
+
+		clock.Advance(time.Second * 59)
+		_, ok := future.TryGet()
+		assert.False(t, ok)
+
+		clock.Advance(time.Second)
+		assert.Equal(t, 42, future.Get())
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		var called atomic.Bool
+		future := async.After(time.Minute, func() int {
+			called.Store(true)
+			return 42
+		}, async.WithClock(clock))
+
+		future.Cancel()
+		_, err := future.Result()
+		assert.ErrorIs(t, err, context.Canceled)
+
+		clock.Advance(time.Hour)
+		assert.False(t, called.Load())
+	})
+
+	t.Run("system clock", func(t *testing.T) {
+		future := async.After(time.Millisecond, func() string { return "done" })
+		assert.Equal(t, "done", future.Get())
+	})
+}
+
+func TestAt(t *testing.T) {
+	t.Run("future time", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		future := async.At(epoch.Add(time.Hour), func() string { return "done" }, async.WithClock(clock))
+
+		clock.Set(epoch.Add(time.Minute))
+		_, ok := future.TryGet()
+		assert.False(t, ok)
+
+		clock.Set(epoch.Add(time.Hour))
+		assert.Equal(t, "done", future.Get())
+	})
+
+	t.Run("past time", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		future := async.At(epoch.Add(-time.Hour), func() string { return "done" }, async.WithClock(clock))
+
+		assert.Equal(t, "done", future.Get())
+	})
+}
+
+func TestEvery(t *testing.T) {
+	t.Run("runs on every tick", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		runs := make(chan struct{})
+
+		ticker := async.Every(time.Second, func(ctx context.Context) {
+			runs <- struct{}{}
+		}, async.WithClock(clock))
+
+		for i := 0; i < 3; i++ {
+			clock.WaitForTimers(1)
+			clock.Advance(time.Second)
+			<-runs
+		}
+
+		ticker.Stop()
+		<-ticker.Done()
+	})
+
+	t.Run("skips overlapping ticks", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		release := make(chan struct{})
+		var runs atomic.Int32
+
+		ticker := async.Every(time.Second, func(ctx context.Context) {
+			runs.Add(1)
+			<-release
+		}, async.WithClock(clock))
+
+		clock.WaitForTimers(1)
+		clock.Advance(time.Second) // starts the first run
+
+		for i := 0; i < 3; i++ { // ticks while the first run is in progress
+			clock.WaitForTimers(1)
+			clock.Advance(time.Second)
+		}
+
+		clock.WaitForTimers(1)
+		close(release)
+		ticker.Stop()
+		<-ticker.Done()
+
+		assert.Equal(t, int32(1), runs.Load())
+	})
+
+	t.Run("overlap", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		ticker := async.Every(time.Second, func(ctx context.Context) {
+			started <- struct{}{}
+			<-release
+		}, async.WithClock(clock), async.WithOverlap())
+
+		for i := 0; i < 3; i++ {
+			clock.WaitForTimers(1)
+			clock.Advance(time.Second)
+			<-started // all three runs are in progress at once
+		}
+
+		close(release)
+		ticker.Stop()
+		<-ticker.Done()
+	})
+
+	t.Run("stop cancels run and waits in Done", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		started := make(chan struct{})
+		var finished atomic.Bool
+
+		ticker := async.Every(time.Second, func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+			finished.Store(true)
+		}, async.WithClock(clock))
+
+		clock.Advance(time.Second)
+		<-started
+
+		ticker.Stop()
+		ticker.Stop()
+		<-ticker.Done()
+		assert.True(t, finished.Load())
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		clock := async.NewFakeClock(epoch)
+		runs := make(chan struct{}, 1)
+
+		ticker := async.Every(time.Second, func(ctx context.Context) {
+			runs <- struct{}{}
+		}, async.WithClock(clock), async.WithJitter(time.Second))
+		defer ticker.Stop()
+
+		clock.Advance(time.Millisecond * 999)
+		select {
+		case <-runs:
+			t.Fatal("run before the interval")
+		case <-time.After(time.Millisecond * 10):
+		}
+
+		clock.Advance(time.Second)
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatal("no run within interval plus jitter")
+		}
+	})
+
+	t.Run("system clock", func(t *testing.T) {
+		var runs atomic.Int32
+		ticker := async.Every(time.Millisecond, func(ctx context.Context) {
+			runs.Add(1)
+		})
+
+		require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+		ticker.Stop()
+		<-ticker.Done()
+	})
+
+	t.Run("non-positive interval", func(t *testing.T) {
+		for _, interval := range []time.Duration{0, -time.Second} {
+			assert.Panics(t, func() { async.Every(interval, func(context.Context) {}) })
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// SingleFlight deduplicates concurrent calls by key: while a call for a key is in flight,
+// every other Do with the same key shares it instead of starting a new one.
+// It protects expensive loads, such as cache fills, from stampedes.
+//
+// A panic in a call is recovered and every caller waiting on it receives a *PanicError.
+//
+// The zero value is ready to use. A SingleFlight must not be copied after first use.
+// All methods are safe for concurrent use by multiple goroutines.
+//
+// Example usage:
+//
+//	var loads async.SingleFlight[string, *User]
+//
+//	func getUser(ctx context.Context, id string) (*User, error) {
+//		return loads.DoContext(ctx, id, func() (*User, error) {
+//			return db.LoadUser(id)
+//		})
+//	}
+type SingleFlight[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*Future[V]
+}
+
+// Do returns the Future of the in-flight call for key, or starts fn in a new goroutine
+// if there is none. The call is forgotten once it is settled, so a later Do starts a new one.
+func (sf *SingleFlight[K, V]) Do(key K, fn func() (V, error)) *Future[V] {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	if f, ok := sf.calls[key]; ok {
+		return f
+	}
+
+	if sf.calls == nil {
+		sf.calls = make(map[K]*Future[V])
+	}
+
+	s := newState[V]()
+	f := &Future[V]{state: s}
+	sf.calls[key] = f
+
+	go func() {
+		v, err := withRecover(fn)()
+
+		sf.mu.Lock()
+		if sf.calls[key] == f {
+			delete(sf.calls, key)
+		}
+		sf.mu.Unlock()
+
+		s.settle(v, err)
+	}()
+
+	return f
+}
+
+// DoContext is like Do, but waits for the result until ctx is done.
+// Giving up on the wait does not cancel the shared call: other callers still receive its result.
+func (sf *SingleFlight[K, V]) DoContext(ctx context.Context, key K, fn func() (V, error)) (V, error) {
+	return sf.Do(key, fn).GetContext(ctx)
+}
+
+// Forget makes the next Do for key start a new call, even if the current one is still in flight.
+// Callers already waiting on the current call still receive its result.
+func (sf *SingleFlight[K, V]) Forget(key K) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	delete(sf.calls, key)
+}
@@ -0,0 +1,145 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+)
+
+func TestSingleFlightDo(t *testing.T) {
+	t.Run("deduplicates concurrent calls", func(t *testing.T) {
+		var sf async.SingleFlight[string, int]
+		var calls atomic.Int32
+		release := make(chan struct{})
+
+		fn := func() (int, error) {
+			calls.Add(1)
+			<-release
+			return 42, nil
+		}
+
+		futures := make([]*async.Future[int], 10)
+		for i := range futures {
+			futures[i] = sf.Do("key", fn)
+		}
+		close(release)
+
+		for _, f := range futures {
+			v, err := f.Result()
+			require.NoError(t, err)
+			assert.Equal(t, 42, v)
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("different keys", func(t *testing.T) {
+		var sf async.SingleFlight[int, int]
+
+		a := sf.Do(1, func() (int, error) { return 1, nil })
+		b := sf.Do(2, func() (int, error) { return 2, nil })
+
+		assert.Equal(t, 1, a.Get())
+		assert.Equal(t, 2, b.Get())
+	})
+
+	t.Run("new call after settle", func(t *testing.T) {
+		var sf async.SingleFlight[string, int]
+		var calls atomic.Int32
+		fn := func() (int, error) { return int(calls.Add(1)), nil }
+
+		assert.Equal(t, 1, sf.Do("key", fn).Get())
+		assert.Equal(t, 2, sf.Do("key", fn).Get())
+	})
+
+	t.Run("error is shared", func(t *testing.T) {
+		var sf async.SingleFlight[string, int]
+		expectedErr := errors.New("failed")
+		release := make(chan struct{})
+
+		a := sf.Do("key", func() (int, error) {
+			<-release
+			return 0, expectedErr
+		})
+		b := sf.Do("key", func() (int, error) { return 1, nil })
+		close(release)
+
+		_, errA := a.Result()
+		_, errB := b.Result()
+		assert.ErrorIs(t, errA, expectedErr)
+		assert.ErrorIs(t, errB, expectedErr)
+	})
+
+	t.Run("panic is propagated to every caller", func(t *testing.T) {
+		var sf async.SingleFlight[string, int]
+		release := make(chan struct{})
+
+		futures := []*async.Future[int]{
+			sf.Do("key", func() (int, error) {
+				<-release
+				panic("boom")
+			}),
+			sf.Do("key", func() (int, error) { return 1, nil }),
+		}
+		close(release)
+
+		for _, f := range futures {
+			var panicErr *async.PanicError
+			_, err := f.Result()
+			assert.ErrorAs(t, err, &panicErr)
+		}
+	})
+}
+
+func TestSingleFlightDoContext(t *testing.T) {
+	var sf async.SingleFlight[string, string]
+	release := make(chan struct{})
+
+	fn := func() (string, error) {
+		<-release
+		return "value", nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	_, err := sf.DoContext(ctx, "key", fn)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The shared call keeps running for other callers.
+	shared := sf.Do("key", func() (string, error) { return "other", nil })
+	close(release)
+
+	v, err := shared.GetContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "value", v)
+}
+
+func TestSingleFlightForget(t *testing.T) {
+	var sf async.SingleFlight[string, int]
+	release := make(chan struct{})
+
+	first := sf.Do("key", func() (int, error) {
+		<-release
+		return 1, nil
+	})
+
+	sf.Forget("key")
+	second := sf.Do("key", func() (int, error) { return 2, nil })
+
+	assert.Equal(t, 2, second.Get())
+	close(release)
+	assert.Equal(t, 1, first.Get())
+
+	// The settled first call must not remove the entry of the second one.
+	third := sf.Do("key", func() (int, error) { return 3, nil })
+	assert.Equal(t, 3, third.Get())
+
+	sf.Forget("missing")
+}
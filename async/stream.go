@@ -0,0 +1,165 @@
+package async
+
+import (
+	"context"
+	"iter"
+)
+
+// StreamOption configures a Stream.
+type StreamOption func(*streamOptions)
+
+type streamOptions struct {
+	buffer int
+}
+
+// WithBuffer sets the number of values the producer of a Stream may send ahead of the consumer.
+// The default is 0: every send waits until the consumer receives the value.
+func WithBuffer(size int) StreamOption {
+	return func(o *streamOptions) {
+		o.buffer = max(size, 0)
+	}
+}
+
+// Stream is a sequence of values produced asynchronously by a goroutine, followed by an
+// optional terminal error. Where a Future holds a single result, a Stream delivers many,
+// for example the items of a paginated API read.
+//
+// The producer sends values with yield, which blocks until the consumer takes them
+// (or the buffer set by WithBuffer has room) and returns false once the Stream is cancelled.
+// The value the producer returns becomes the terminal error of the Stream.
+// A panic in the producer is recovered and becomes a *PanicError.
+//
+// Values are read once, either with All or through C; a Stream has a single consumer.
+// A producer whose values are never read stays blocked until the Stream is cancelled.
+//
+// All methods are safe for concurrent use by multiple goroutines.
+//
+// Example usage:
+//
+//	users := async.NewStream(ctx, func(ctx context.Context, yield func(User) bool) error {
+//		for page := 1; ; page++ {
+//			batch, err := api.ListUsers(ctx, page)
+//			if err != nil || len(batch) == 0 {
+//				return err
+//			}
+//			for _, u := range batch {
+//				if !yield(u) {
+//					return nil
+//				}
+//			}
+//		}
+//	})
+//
+//	for u, err := range users.All() {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(u.Name)
+//	}
+type Stream[T any] struct {
+	ch     chan T
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// NewStream starts producer in a new goroutine and returns the Stream it feeds.
+// The context passed to producer is cancelled when ctx is done, when Cancel is called,
+// or when the consumer stops an All loop early. It accepts WithBuffer.
+func NewStream[T any](ctx context.Context, producer func(ctx context.Context, yield func(T) bool) error, opts ...StreamOption) *Stream[T] {
+	var o streamOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Stream[T]{
+		ch:     make(chan T, o.buffer),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		_, err := withRecover(func() (struct{}, error) {
+			return struct{}{}, producer(ctx, s.send)
+		})()
+		if err == nil {
+			err = ctx.Err()
+		}
+
+		s.err = err
+		close(s.ch)
+		close(s.done)
+		cancel()
+	}()
+
+	return s
+}
+
+// All returns an iterator over the values of the Stream. After the last value, it yields
+// the terminal error, if any, with the zero value of T. Stopping the loop early cancels the Stream.
+func (s *Stream[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for v := range s.ch {
+			if !yield(v, nil) {
+				s.Cancel()
+				return
+			}
+		}
+
+		if s.err != nil {
+			var zero T
+			yield(zero, s.err)
+		}
+	}
+}
+
+// C returns the channel the values of the Stream are delivered on.
+// The channel is closed once the producer has returned; Err then reports why.
+func (s *Stream[T]) C() <-chan T {
+	return s.ch
+}
+
+// Err waits for the producer to return and returns the terminal error of the Stream:
+// the error returned by the producer, a *PanicError, or the context error if the Stream
+// was cancelled. It returns nil if the producer finished successfully.
+func (s *Stream[T]) Err() error {
+	<-s.done
+	return s.err
+}
+
+// Done returns a channel that is closed once the producer has returned.
+func (s *Stream[T]) Done() <-chan struct{} {
+	return s.done
+}
+
+// Collect reads the remaining values of the Stream into a slice and returns them
+// together with the terminal error.
+func (s *Stream[T]) Collect() ([]T, error) {
+	var values []T
+	for v := range s.ch {
+		values = append(values, v)
+	}
+	return values, s.err
+}
+
+// Cancel stops the Stream: the next yield of the producer returns false and its context is cancelled.
+// Values still buffered remain readable. Calling Cancel more than once has no further effect.
+func (s *Stream[T]) Cancel() {
+	s.cancel()
+}
+
+func (s *Stream[T]) send(v T) bool {
+	if s.ctx.Err() != nil {
+		return false
+	}
+
+	select {
+	case s.ch <- v:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,156 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+)
+
+func count(n int) func(ctx context.Context, yield func(int) bool) error {
+	return func(ctx context.Context, yield func(int) bool) error {
+		for i := 1; i <= n; i++ {
+			if !yield(i) {
+				return nil
+			}
+		}
+		return nil
+	}
+}
+
+func TestStream(t *testing.T) {
+	t.Run("all", func(t *testing.T) {
+		s := async.NewStream(context.Background(), count(5))
+
+		var got []int
+		for v, err := range s.All() {
+			require.NoError(t, err)
+			got = append(got, v)
+		}
+
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, got)
+		assert.NoError(t, s.Err())
+	})
+
+	t.Run("terminal error", func(t *testing.T) {
+		fail := errors.New("page 3 failed")
+		s := async.NewStream(context.Background(), func(ctx context.Context, yield func(int) bool) error {
+			yield(1)
+			yield(2)
+			return fail
+		})
+
+		var got []int
+		var gotErr error
+		for v, err := range s.All() {
+			if err != nil {
+				gotErr = err
+				break
+			}
+			got = append(got, v)
+		}
+
+		assert.Equal(t, []int{1, 2}, got)
+		assert.ErrorIs(t, gotErr, fail)
+		assert.ErrorIs(t, s.Err(), fail)
+	})
+
+	t.Run("channel", func(t *testing.T) {
+		s := async.NewStream(context.Background(), count(3), async.WithBuffer(3))
+
+		var got []int
+		for v := range s.C() {
+			got = append(got, v)
+		}
+
+		assert.Equal(t, []int{1, 2, 3}, got)
+		assert.NoError(t, s.Err())
+	})
+
+	t.Run("collect", func(t *testing.T) {
+		got, err := async.NewStream(context.Background(), count(4)).Collect()
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3, 4}, got)
+	})
+
+	t.Run("backpressure", func(t *testing.T) {
+		sent := make(chan int, 10)
+		s := async.NewStream(context.Background(), func(ctx context.Context, yield func(int) bool) error {
+			for i := 1; i <= 10; i++ {
+				if !yield(i) {
+					return nil
+				}
+				sent <- i
+			}
+			return nil
+		}, async.WithBuffer(2))
+
+		time.Sleep(time.Millisecond * 20)
+		assert.Len(t, sent, 2, "the producer must not run ahead of the buffer")
+
+		got, err := s.Collect()
+		require.NoError(t, err)
+		assert.Len(t, got, 10)
+	})
+
+	t.Run("break cancels the producer", func(t *testing.T) {
+		s := async.NewStream(context.Background(), func(ctx context.Context, yield func(int) bool) error {
+			for i := 0; ; i++ {
+				if !yield(i) {
+					return ctx.Err()
+				}
+			}
+		})
+
+		for v := range s.All() {
+			if v == 3 {
+				break
+			}
+		}
+
+		assert.ErrorIs(t, s.Err(), context.Canceled)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		s := async.NewStream(context.Background(), func(ctx context.Context, yield func(int) bool) error {
+			<-ctx.Done()
+			return nil
+		})
+
+		s.Cancel()
+		<-s.Done()
+
+		_, ok := <-s.C()
+		assert.False(t, ok)
+		assert.ErrorIs(t, s.Err(), context.Canceled)
+	})
+
+	t.Run("parent context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		s := async.NewStream(ctx, count(1_000_000))
+
+		<-s.C()
+		cancel()
+
+		_, err := s.Collect()
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		s := async.NewStream(context.Background(), func(ctx context.Context, yield func(int) bool) error {
+			yield(1)
+			panic("boom")
+		})
+
+		got, err := s.Collect()
+		assert.Equal(t, []int{1}, got)
+
+		var panicErr *async.PanicError
+		assert.ErrorAs(t, err, &panicErr)
+	})
+}
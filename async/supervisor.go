@@ -0,0 +1,337 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lif0/pkg/errx"
+)
+
+// RestartStrategy decides which children a Supervisor restarts when one of them fails.
+type RestartStrategy int
+
+const (
+	// RestartOneForOne restarts only the failed child. It is the default.
+	RestartOneForOne RestartStrategy = iota
+	// RestartOneForAll stops every other running child and restarts them all with the failed one.
+	RestartOneForAll
+	// RestartRestForOne stops the running children added after the failed one and restarts them
+	// with it. It suits children that depend on the ones added before them.
+	RestartRestForOne
+)
+
+// SupervisorOption configures a Supervisor. It is WithStrategy, WithIntensity, WithRestartBackoff
+// or a ClockOption.
+type SupervisorOption interface {
+	applySupervisor(o *supervisorOptions)
+}
+
+type supervisorOptionFunc func(*supervisorOptions)
+
+func (f supervisorOptionFunc) applySupervisor(o *supervisorOptions) { f(o) }
+
+type supervisorOptions struct {
+	strategy    RestartStrategy
+	maxRestarts int
+	period      time.Duration
+	backoff     Backoff
+	clock       Clock
+}
+
+func (c ClockOption) applySupervisor(o *supervisorOptions) {
+	if c.clock != nil {
+		o.clock = c.clock
+	}
+}
+
+// WithStrategy sets which children a Supervisor restarts when one fails. The default is RestartOneForOne.
+func WithStrategy(strategy RestartStrategy) SupervisorOption {
+	return supervisorOptionFunc(func(o *supervisorOptions) {
+		o.strategy = strategy
+	})
+}
+
+// WithIntensity makes a Supervisor give up once more than maxRestarts restarts happen within period:
+// it stops every child and Run returns ErrRestartIntensity. The default, and a period of 0 or less,
+// is no limit.
+func WithIntensity(maxRestarts int, period time.Duration) SupervisorOption {
+	return supervisorOptionFunc(func(o *supervisorOptions) {
+		o.maxRestarts = max(maxRestarts, 0)
+		o.period = period
+	})
+}
+
+// WithRestartBackoff sets the delay before each restart. The attempt passed to backoff is the number
+// of times the failed child has been restarted, starting at 1. A child that ran longer than both the
+// intensity period and its last delay before failing is healthy again: its count starts over at 1.
+// By default children restart at once.
+func WithRestartBackoff(backoff Backoff) SupervisorOption {
+	return supervisorOptionFunc(func(o *supervisorOptions) {
+		o.backoff = backoff
+	})
+}
+
+// Supervisor runs long-lived child services and restarts them when they fail,
+// in the spirit of Erlang/OTP supervisors.
+//
+// A child fails when it returns an error or panics; a child that returns nil is finished and
+// is not restarted. Which children are restarted is set by the RestartStrategy. WithIntensity
+// limits how often restarts may happen and WithRestartBackoff delays them.
+//
+// Children must be added before Run is called, and a Supervisor must not be run more than once.
+//
+// Example usage:
+//
+//	sup := async.NewSupervisor(
+//		async.WithIntensity(5, time.Minute),
+//		async.WithRestartBackoff(async.ExponentialBackoff(100*time.Millisecond, 10*time.Second)),
+//	)
+//	sup.Add("consumer", consumer.Run)
+//	sup.Add("reporter", reporter.Run)
+//
+//	// Runs until ctx is canceled, e.g. on SIGTERM, or until the children fail too often.
+//	if err := sup.Run(ctx); err != nil {
+//		log.Println(err)
+//	}
+type Supervisor struct {
+	strategy    RestartStrategy
+	maxRestarts int
+	period      time.Duration
+	backoff     Backoff
+	clock       Clock
+
+	children []*child
+	restarts []time.Time // recent restarts, for WithIntensity
+	exits    chan *childRun
+	quit     chan struct{} // closed when Run returns
+}
+
+type child struct {
+	name     string
+	fn       func(ctx context.Context) error
+	run      *childRun // nil while the child is not running
+	restarts int
+	delay    time.Duration
+}
+
+type childRun struct {
+	index   int
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started time.Time
+	done    chan struct{}
+	err     error
+}
+
+// NewSupervisor returns a Supervisor without children.
+// It accepts WithStrategy, WithIntensity, WithRestartBackoff and WithClock.
+func NewSupervisor(opts ...SupervisorOption) *Supervisor {
+	o := supervisorOptions{clock: SystemClock()}
+	for _, opt := range opts {
+		opt.applySupervisor(&o)
+	}
+
+	return &Supervisor{
+		strategy:    o.strategy,
+		maxRestarts: o.maxRestarts,
+		period:      o.period,
+		backoff:     o.backoff,
+		clock:       o.clock,
+		exits:       make(chan *childRun),
+		quit:        make(chan struct{}),
+	}
+}
+
+// Add adds a child named name that runs fn. Children are started in the order they are added.
+func (s *Supervisor) Add(name string, fn func(ctx context.Context) error) {
+	s.children = append(s.children, &child{name: name, fn: fn})
+}
+
+// Run starts the children and supervises them until ctx is done, every child has finished,
+// or the restart intensity is exceeded. It then stops the running children, waits for them
+// to return and returns their exit errors as an errx.MultiError, each prefixed with the name
+// of its child. A child that returns the context error on shutdown exits cleanly.
+// If the restart intensity was exceeded, the first error wraps ErrRestartIntensity.
+// Run returns nil if every child exited cleanly.
+func (s *Supervisor) Run(ctx context.Context) error {
+	defer close(s.quit)
+
+	var errs errx.MultiError
+
+	for i := range s.children {
+		s.start(ctx, i)
+	}
+
+	for s.running() {
+		var r *childRun
+
+		select {
+		case <-ctx.Done():
+			return s.shutdown(&errs)
+		case r = <-s.exits:
+		}
+
+		c := s.children[r.index]
+		if c.run != r {
+			continue // stopped by a restart of its siblings
+		}
+
+		if r.err == nil {
+			c.run = nil
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return s.shutdown(&errs)
+		}
+
+		if !s.allowRestart() {
+			errs.Append(fmt.Errorf("%w: child %q: %w", ErrRestartIntensity, c.name, r.err))
+			c.run = nil
+			return s.shutdown(&errs)
+		}
+
+		restart := s.affected(r.index)
+		s.stop(restart, nil)
+
+		if s.clock.Now().Sub(r.started) > max(s.period, c.delay) {
+			// The child ran healthily before failing: do not hold its past failures against it.
+			c.restarts = 0
+			c.delay = 0
+		}
+
+		c.restarts++
+		if s.backoff != nil {
+			c.delay = s.backoff(c.restarts, c.delay)
+		}
+
+		if err := sleep(ctx, s.clock, c.delay); err != nil {
+			return s.shutdown(&errs)
+		}
+
+		for _, i := range restart {
+			s.start(ctx, i)
+		}
+	}
+
+	return s.shutdown(&errs)
+}
+
+// shutdown stops every running child and returns errs with their exit errors, or nil if there are none.
+func (s *Supervisor) shutdown(errs *errx.MultiError) error {
+	s.stop(s.all(), errs)
+
+	if errs.IsEmpty() {
+		return nil
+	}
+
+	return *errs
+}
+
+// start runs the child at index i in a new goroutine.
+func (s *Supervisor) start(ctx context.Context, i int) {
+	ctx, cancel := context.WithCancel(ctx)
+	r := &childRun{index: i, ctx: ctx, cancel: cancel, started: s.clock.Now(), done: make(chan struct{})}
+	s.children[i].run = r
+
+	fn := s.children[i].fn
+	go func() {
+		_, r.err = withRecover(func() (struct{}, error) {
+			return struct{}{}, fn(ctx)
+		})()
+		cancel()
+		close(r.done)
+
+		select {
+		case s.exits <- r:
+		case <-s.quit:
+		}
+	}()
+}
+
+// stop cancels the running children at the given indices, waits for them to return and appends
+// their exit errors to errs, if not nil, except the context errors caused by the cancellation.
+func (s *Supervisor) stop(indices []int, errs *errx.MultiError) {
+	for _, i := range indices {
+		if r := s.children[i].run; r != nil {
+			r.cancel()
+		}
+	}
+
+	for _, i := range indices {
+		c := s.children[i]
+		if c.run == nil {
+			continue
+		}
+
+		<-c.run.done
+		if err := c.run.err; errs != nil && err != nil && !errors.Is(err, c.run.ctx.Err()) {
+			errs.Append(fmt.Errorf("child %q: %w", c.name, err))
+		}
+		c.run = nil
+	}
+}
+
+// affected returns the indices of the children to restart when the child at index i fails.
+func (s *Supervisor) affected(i int) []int {
+	var indices []int
+
+	switch s.strategy {
+	case RestartOneForAll:
+		for j, c := range s.children {
+			if j == i || c.run != nil {
+				indices = append(indices, j)
+			}
+		}
+	case RestartRestForOne:
+		indices = append(indices, i)
+		for j := i + 1; j < len(s.children); j++ {
+			if s.children[j].run != nil {
+				indices = append(indices, j)
+			}
+		}
+	default:
+		indices = append(indices, i)
+	}
+
+	return indices
+}
+
+// allowRestart records a restart and reports whether it stays within the restart intensity.
+func (s *Supervisor) allowRestart() bool {
+	if s.period <= 0 {
+		return true
+	}
+
+	now := s.clock.Now()
+	s.restarts = append(s.restarts, now)
+
+	recent := s.restarts[:0]
+	for _, t := range s.restarts {
+		if now.Sub(t) <= s.period {
+			recent = append(recent, t)
+		}
+	}
+	s.restarts = recent
+
+	return len(s.restarts) <= s.maxRestarts
+}
+
+func (s *Supervisor) all() []int {
+	indices := make([]int, len(s.children))
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
+
+func (s *Supervisor) running() bool {
+	for _, c := range s.children {
+		if c.run != nil {
+			return true
+		}
+	}
+	return false
+}
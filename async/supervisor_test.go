@@ -0,0 +1,283 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lif0/pkg/async"
+	"github.com/lif0/pkg/errx"
+)
+
+// service returns a child whose first failures runs fail and whose later runs last until canceled.
+// It counts its runs in starts and signals every run on started.
+func service(failures int32, starts *atomic.Int32, started chan<- struct{}) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		n := starts.Add(1)
+		if started != nil {
+			started <- struct{}{}
+		}
+		if n <= failures {
+			return errors.New("crashed")
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+}
+
+// runSupervisor runs sup until want runs have been signaled on started, then cancels it.
+func runSupervisor(t *testing.T, sup *async.Supervisor, started <-chan struct{}, want int) error {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- sup.Run(ctx) }()
+
+	for range want {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("children were not started")
+		}
+	}
+
+	cancel()
+	return <-done
+}
+
+func TestSupervisor(t *testing.T) {
+	t.Run("one for one", func(t *testing.T) {
+		var flaky, steady atomic.Int32
+		started := make(chan struct{}, 10)
+
+		sup := async.NewSupervisor()
+		sup.Add("flaky", service(2, &flaky, started))
+		sup.Add("steady", service(0, &steady, started))
+
+		require.NoError(t, runSupervisor(t, sup, started, 4))
+		assert.Equal(t, int32(3), flaky.Load())
+		assert.Equal(t, int32(1), steady.Load())
+	})
+
+	t.Run("one for all", func(t *testing.T) {
+		var a, b atomic.Int32
+		started := make(chan struct{}, 10)
+
+		sup := async.NewSupervisor(async.WithStrategy(async.RestartOneForAll))
+		sup.Add("a", service(0, &a, started))
+		sup.Add("b", service(1, &b, started))
+
+		require.NoError(t, runSupervisor(t, sup, started, 4))
+		assert.Equal(t, int32(2), a.Load())
+		assert.Equal(t, int32(2), b.Load())
+	})
+
+	t.Run("rest for one", func(t *testing.T) {
+		var a, b, c atomic.Int32
+		started := make(chan struct{}, 10)
+
+		sup := async.NewSupervisor(async.WithStrategy(async.RestartRestForOne))
+		sup.Add("a", service(0, &a, started))
+		sup.Add("b", service(1, &b, started))
+		sup.Add("c", service(0, &c, started))
+
+		require.NoError(t, runSupervisor(t, sup, started, 5))
+		assert.Equal(t, int32(1), a.Load())
+		assert.Equal(t, int32(2), b.Load())
+		assert.Equal(t, int32(2), c.Load())
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		var runs atomic.Int32
+		started := make(chan struct{}, 10)
+
+		sup := async.NewSupervisor()
+		sup.Add("panicky", func(ctx context.Context) error {
+			started <- struct{}{}
+			if runs.Add(1) == 1 {
+				panic("boom")
+			}
+			<-ctx.Done()
+			return nil
+		})
+
+		require.NoError(t, runSupervisor(t, sup, started, 2))
+		assert.Equal(t, int32(2), runs.Load())
+	})
+
+	t.Run("intensity", func(t *testing.T) {
+		var runs atomic.Int32
+
+		sup := async.NewSupervisor(async.WithIntensity(2, time.Minute))
+		sup.Add("broken", service(100, &runs, nil))
+		sup.Add("steady", service(0, new(atomic.Int32), nil))
+
+		err := sup.Run(context.Background())
+		assert.ErrorIs(t, err, async.ErrRestartIntensity)
+		assert.Equal(t, int32(3), runs.Load())
+
+		var errs errx.MultiError
+		require.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 1, "a child stopped by the supervisor must exit cleanly")
+	})
+
+	t.Run("intensity without period", func(t *testing.T) {
+		var runs atomic.Int32
+		started := make(chan struct{}, 10)
+
+		sup := async.NewSupervisor(async.WithIntensity(1, 0))
+		sup.Add("flaky", service(3, &runs, started))
+
+		require.NoError(t, runSupervisor(t, sup, started, 4), "a period of 0 must not limit restarts")
+		assert.Equal(t, int32(4), runs.Load())
+	})
+
+	t.Run("intensity window on clock", func(t *testing.T) {
+		clock := async.NewFakeClock(time.Now())
+		started := make(chan struct{})
+		crash := make(chan struct{})
+
+		sup := async.NewSupervisor(async.WithIntensity(1, time.Minute), async.WithClock(clock))
+		sup.Add("crashy", func(ctx context.Context) error {
+			started <- struct{}{}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-crash:
+				return errors.New("crashed")
+			}
+		})
+
+		done := make(chan error, 1)
+		go func() { done <- sup.Run(context.Background()) }()
+
+		<-started
+		crash <- struct{}{}
+		<-started // first restart
+
+		clock.Advance(time.Minute * 2) // the first restart leaves the window
+		crash <- struct{}{}
+		<-started // second restart, alone in the window
+
+		crash <- struct{}{} // third restart, the second one in the window
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, async.ErrRestartIntensity)
+		case <-time.After(time.Second):
+			t.Fatal("supervisor did not give up")
+		}
+	})
+
+	t.Run("backoff on clock", func(t *testing.T) {
+		clock := async.NewFakeClock(time.Now())
+		var runs atomic.Int32
+		started := make(chan struct{}, 10)
+
+		sup := async.NewSupervisor(async.WithRestartBackoff(async.ConstantBackoff(time.Hour)), async.WithClock(clock))
+		sup.Add("flaky", service(1, &runs, started))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- sup.Run(ctx) }()
+
+		<-started
+		clock.WaitForTimers(1)
+		assert.Equal(t, int32(1), runs.Load(), "the restart must wait for the backoff")
+
+		clock.Advance(time.Hour)
+		<-started
+		assert.Equal(t, int32(2), runs.Load())
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("backoff resets after a healthy run", func(t *testing.T) {
+		clock := async.NewFakeClock(time.Now())
+		started := make(chan struct{})
+		crash := make(chan struct{})
+
+		sup := async.NewSupervisor(async.WithRestartBackoff(async.ExponentialBackoff(time.Minute, time.Hour)), async.WithClock(clock))
+		sup.Add("crashy", func(ctx context.Context) error {
+			started <- struct{}{}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-crash:
+				return errors.New("crashed")
+			}
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- sup.Run(ctx) }()
+
+		restartAfter := func(d time.Duration) {
+			t.Helper()
+			crash <- struct{}{}
+			clock.WaitForTimers(1)
+			clock.Advance(d)
+			select {
+			case <-started:
+			case <-time.After(time.Second):
+				t.Fatalf("child was not restarted after %v", d)
+			}
+		}
+
+		<-started
+		restartAfter(time.Minute)
+		restartAfter(time.Minute * 2) // the delay doubles while the child keeps failing
+
+		clock.Advance(time.Hour * 24) // a day of healthy running
+		restartAfter(time.Minute)
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		var runs atomic.Int32
+		started := make(chan struct{}, 10)
+
+		sup := async.NewSupervisor(async.WithRestartBackoff(async.ConstantBackoff(time.Millisecond * 20)))
+		sup.Add("flaky", service(2, &runs, started))
+
+		start := time.Now()
+		require.NoError(t, runSupervisor(t, sup, started, 3))
+		assert.GreaterOrEqual(t, time.Since(start), time.Millisecond*40)
+	})
+
+	t.Run("finished children", func(t *testing.T) {
+		sup := async.NewSupervisor()
+		sup.Add("once", func(ctx context.Context) error { return nil })
+		sup.Add("twice", func(ctx context.Context) error { return nil })
+
+		assert.NoError(t, sup.Run(context.Background()))
+	})
+
+	t.Run("exit errors on shutdown", func(t *testing.T) {
+		fail := errors.New("flush failed")
+		started := make(chan struct{}, 10)
+
+		sup := async.NewSupervisor()
+		sup.Add("clean", service(0, new(atomic.Int32), started))
+		sup.Add("dirty", func(ctx context.Context) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			return fail
+		})
+
+		err := runSupervisor(t, sup, started, 2)
+
+		var errs errx.MultiError
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 1)
+		assert.ErrorIs(t, err, fail)
+		assert.EqualError(t, errs[0], `child "dirty": flush failed`)
+	})
+}
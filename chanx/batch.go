@@ -0,0 +1,157 @@
+package chanx
+
+import (
+	"context"
+	"time"
+)
+
+// BatchOption configures Batch. It is either WithReuse or WithBuffer.
+type BatchOption interface {
+	applyBatch(o *batchOptions)
+}
+
+type batchOptions struct {
+	options
+	reuse bool
+}
+
+type batchOptionFunc func(*batchOptions)
+
+func (f batchOptionFunc) applyBatch(o *batchOptions) { f(o) }
+
+func (b BufferOption) applyBatch(o *batchOptions) { b.apply(&o.options) }
+
+// WithReuse makes Batch reuse the memory of the slices it emits instead of allocating
+// a new slice per batch. A batch is then valid only until the reader receives the next one,
+// and must be copied to be kept longer.
+func WithReuse() BatchOption {
+	return batchOptionFunc(func(o *batchOptions) {
+		o.reuse = true
+	})
+}
+
+// Batch groups the values of in into slices of up to maxSize values. A batch is emitted when
+// it reaches maxSize or when maxWait has passed since its first value, whichever comes first;
+// if maxWait is 0 or less, only the size counts. If maxSize is less than 1, it is treated as 1.
+//
+// When in is closed, the values collected so far are emitted as a last, smaller batch and the
+// output is closed; the reader must therefore keep reading until the output is closed.
+// When the context is canceled, the last batch is emitted only if a reader is ready for it or
+// the output buffer has room, and dropped otherwise. It accepts WithBuffer and WithReuse.
+//
+// Example usage:
+//
+//	for rows := range chanx.Batch(ctx, rows, 500, time.Second) {
+//		db.BulkInsert(ctx, rows) // at most 500 rows, at most a second late
+//	}
+func Batch[T any](ctx context.Context, in <-chan T, maxSize int, maxWait time.Duration, opts ...BatchOption) <-chan []T {
+	var o batchOptions
+	for _, opt := range opts {
+		opt.applyBatch(&o)
+	}
+
+	maxSize = max(maxSize, 1)
+	out := make(chan []T, o.buffer)
+
+	go func() {
+		defer close(out)
+
+		var (
+			pool    = newBatchPool[T](maxSize, o)
+			batch   = pool.next()
+			timer   = time.NewTimer(maxWait)
+			timeout <-chan time.Time
+		)
+
+		timer.Stop()
+		defer timer.Stop()
+
+		// emit sends the batch, unless the context is canceled first.
+		emit := func() bool {
+			timer.Stop()
+			timeout = nil
+
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- batch:
+				batch = pool.next()
+				return true
+			}
+		}
+
+		// Flush the values collected so far; after a cancel, only if that needs no waiting.
+		defer func() {
+			if len(batch) == 0 {
+				return
+			}
+
+			select {
+			case out <- batch:
+			default:
+				select {
+				case <-ctx.Done():
+				case out <- batch:
+				}
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+
+				batch = append(batch, v)
+				if len(batch) == 1 && maxWait > 0 {
+					timer.Reset(maxWait)
+					timeout = timer.C
+				}
+
+				if len(batch) >= maxSize && !emit() {
+					return
+				}
+			case <-timeout:
+				if !emit() {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// batchPool hands out the slices Batch fills. With WithReuse it cycles through enough slices
+// that none is overwritten while the reader may still hold it: one per buffered batch, one the
+// reader is processing and one being filled.
+type batchPool[T any] struct {
+	size  int
+	slots [][]T
+	i     int
+}
+
+func newBatchPool[T any](size int, o batchOptions) *batchPool[T] {
+	p := &batchPool[T]{size: size}
+	if o.reuse {
+		p.slots = make([][]T, o.buffer+2)
+	}
+
+	return p
+}
+
+func (p *batchPool[T]) next() []T {
+	if p.slots == nil {
+		return make([]T, 0, p.size)
+	}
+
+	p.i = (p.i + 1) % len(p.slots)
+	if p.slots[p.i] == nil {
+		p.slots[p.i] = make([]T, 0, p.size)
+	}
+
+	return p.slots[p.i][:0]
+}
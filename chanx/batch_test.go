@@ -0,0 +1,100 @@
+package chanx_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/lif0/pkg/chanx"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBatchSize verifies that Batch emits full batches and the remainder when the input is closed.
+func TestBatchSize(t *testing.T) {
+	var got [][]int
+	for batch := range chanx.Batch(context.Background(), generate(10), 3, 0) {
+		got = append(got, batch)
+	}
+
+	assert.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}, {10}}, got)
+}
+
+// TestBatchMinSize verifies that a maxSize below 1 emits every value on its own.
+func TestBatchMinSize(t *testing.T) {
+	var got [][]int
+	for batch := range chanx.Batch(context.Background(), generate(2), 0, 0) {
+		got = append(got, batch)
+	}
+
+	assert.Equal(t, [][]int{{1}, {2}}, got)
+}
+
+// TestBatchWait verifies that Batch emits a partial batch once maxWait has passed since its first value.
+func TestBatchWait(t *testing.T) {
+	in := make(chan int)
+	defer close(in)
+	out := chanx.Batch(context.Background(), in, 100, time.Millisecond*20)
+
+	start := time.Now()
+	in <- 1
+	in <- 2
+
+	batch, ok := receive(t, out)
+	assert.True(t, ok)
+	assert.Equal(t, []int{1, 2}, batch)
+	assert.GreaterOrEqual(t, time.Since(start), time.Millisecond*20)
+
+	in <- 3
+	batch, _ = receive(t, out)
+	assert.Equal(t, []int{3}, batch, "the wait must restart with the first value of the next batch")
+}
+
+// TestBatchContextCancel verifies that canceling the context flushes the collected values
+// into a buffered output and closes it.
+func TestBatchContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := chanx.Batch(ctx, in, 100, time.Hour, chanx.WithBuffer(1))
+
+	in <- 1
+	in <- 2
+	cancel()
+
+	batch, ok := receive(t, out)
+	assert.True(t, ok)
+	assert.Equal(t, []int{1, 2}, batch)
+
+	_, ok = receive(t, out)
+	assert.False(t, ok)
+}
+
+// TestBatchContextCancelNoReader verifies that after a cancel, a last batch nobody is ready to read
+// is dropped rather than blocking the goroutine forever.
+func TestBatchContextCancelNoReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := chanx.Batch(ctx, in, 100, time.Hour)
+
+	in <- 1
+	in <- 2
+	cancel()
+	time.Sleep(time.Millisecond * 20) // a goroutine still blocked on the send would hand over the batch below
+
+	_, ok := receive(t, out)
+	assert.False(t, ok, "the output must be closed without the last batch")
+}
+
+// TestBatchReuse verifies that WithReuse recycles the memory of earlier batches without corrupting the current one.
+func TestBatchReuse(t *testing.T) {
+	var got [][]int
+	var first []*int
+	for batch := range chanx.Batch(context.Background(), generate(9), 3, 0, chanx.WithReuse()) {
+		got = append(got, slices.Clone(batch))
+		first = append(first, &batch[0])
+	}
+
+	assert.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}, got)
+	assert.NotSame(t, first[0], first[1], "the batch being read must not be overwritten")
+	assert.Same(t, first[0], first[2], "the memory of an earlier batch must be reused")
+}
@@ -0,0 +1,228 @@
+package chanx
+
+import (
+	"context"
+	"sync"
+)
+
+// SlowConsumerPolicy decides what a Broadcaster does when a subscriber's channel is full.
+type SlowConsumerPolicy int
+
+const (
+	// Block waits until the subscriber takes the value, which holds back every other subscriber.
+	// It is the default.
+	Block SlowConsumerPolicy = iota
+	// DropNewest discards the value that does not fit.
+	DropNewest
+	// DropOldest discards the oldest buffered value to make room for the new one.
+	// On an unbuffered channel it behaves like DropNewest.
+	DropOldest
+	// Disconnect unsubscribes the subscriber and closes its channel.
+	Disconnect
+)
+
+// SubscribeOption configures a subscriber of a Broadcaster. It is either WithPolicy or WithBuffer.
+type SubscribeOption interface {
+	applySubscribe(o *subscribeOptions)
+}
+
+type subscribeOptions struct {
+	options
+	policy SlowConsumerPolicy
+}
+
+type subscribeOptionFunc func(*subscribeOptions)
+
+func (f subscribeOptionFunc) applySubscribe(o *subscribeOptions) { f(o) }
+
+func (b BufferOption) applySubscribe(o *subscribeOptions) { b.apply(&o.options) }
+
+// WithPolicy sets what a Broadcaster does when the channel of a subscriber is full.
+// The default is Block.
+func WithPolicy(policy SlowConsumerPolicy) SubscribeOption {
+	return subscribeOptionFunc(func(o *subscribeOptions) {
+		o.policy = policy
+	})
+}
+
+// Broadcaster copies every value of an input channel to a changing set of subscribers.
+// Unlike Tee, subscribers come and go with Subscribe and Unsubscribe, and each one has its own
+// buffer and SlowConsumerPolicy, so that one slow listener need not hold back the others.
+//
+// A subscriber receives the values published after it subscribed. Every subscriber channel is
+// closed when the input is closed or the context is canceled.
+//
+// All methods are safe for concurrent use by multiple goroutines.
+//
+// Example usage:
+//
+//	b := chanx.NewBroadcaster(ctx, events)
+//
+//	audit := b.Subscribe()                                                    // sees every event
+//	ui := b.Subscribe(chanx.WithBuffer(100), chanx.WithPolicy(chanx.DropOldest)) // may skip events
+//	defer b.Unsubscribe(ui)
+type Broadcaster[T any] struct {
+	mu     sync.Mutex
+	subs   map[<-chan T]*subscriber[T]
+	closed bool
+
+	// index maps a channel to its subscriber like subs, but can be read while
+	// a publish blocked on that subscriber holds mu.
+	index sync.Map
+}
+
+type subscriber[T any] struct {
+	ch       chan T
+	policy   SlowConsumerPolicy
+	quit     chan struct{} // closed by Unsubscribe to release a blocked send
+	quitOnce sync.Once
+}
+
+// NewBroadcaster starts a Broadcaster that reads in until it is closed or the context is canceled.
+func NewBroadcaster[T any](ctx context.Context, in <-chan T) *Broadcaster[T] {
+	b := &Broadcaster[T]{
+		subs: make(map[<-chan T]*subscriber[T]),
+	}
+
+	go b.run(ctx, in)
+
+	return b
+}
+
+// Subscribe returns a new channel that receives the values published from now on.
+// If the Broadcaster has already stopped, the channel is closed. It accepts WithBuffer and WithPolicy.
+func (b *Broadcaster[T]) Subscribe(opts ...SubscribeOption) <-chan T {
+	var o subscribeOptions
+	for _, opt := range opts {
+		opt.applySubscribe(&o)
+	}
+
+	s := &subscriber[T]{
+		ch:     make(chan T, o.buffer),
+		policy: o.policy,
+		quit:   make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(s.ch)
+		return s.ch
+	}
+
+	b.subs[s.ch] = s
+	b.index.Store((<-chan T)(s.ch), s)
+
+	return s.ch
+}
+
+// Unsubscribe removes the subscriber of ch and closes ch. Values still buffered in ch remain readable.
+// Unsubscribing a channel that is not subscribed has no effect.
+func (b *Broadcaster[T]) Unsubscribe(ch <-chan T) {
+	v, ok := b.index.Load(ch)
+	if !ok {
+		return
+	}
+
+	// Release a send blocked on this subscriber before taking the lock it holds.
+	s := v.(*subscriber[T])
+	s.quitOnce.Do(func() { close(s.quit) })
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(ch)
+}
+
+func (b *Broadcaster[T]) run(ctx context.Context, in <-chan T) {
+	defer b.stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case v, ok := <-in:
+			if !ok {
+				return
+			}
+
+			if !b.publish(ctx, v) {
+				return
+			}
+		}
+	}
+}
+
+// publish sends v to every subscriber according to its policy.
+// It reports false if the context was canceled.
+func (b *Broadcaster[T]) publish(ctx context.Context, v T) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, s := range b.subs {
+		switch s.policy {
+		case DropNewest:
+			select {
+			case s.ch <- v:
+			default:
+			}
+
+		case DropOldest:
+			select {
+			case s.ch <- v:
+				continue
+			default:
+			}
+
+			select {
+			case <-s.ch: // make room; only this goroutine sends, so the next send succeeds
+			default:
+			}
+
+			select {
+			case s.ch <- v:
+			default: // an unbuffered channel without a ready reader
+			}
+
+		case Disconnect:
+			select {
+			case s.ch <- v:
+			default:
+				b.remove(key)
+			}
+
+		default:
+			select {
+			case <-ctx.Done():
+				return false
+			case <-s.quit:
+			case s.ch <- v:
+			}
+		}
+	}
+
+	return true
+}
+
+func (b *Broadcaster[T]) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for key := range b.subs {
+		b.remove(key)
+	}
+}
+
+// remove unsubscribes the subscriber of ch and closes ch, if it is still subscribed. b.mu must be held.
+func (b *Broadcaster[T]) remove(ch <-chan T) {
+	s, ok := b.subs[ch]
+	if !ok {
+		return
+	}
+
+	delete(b.subs, ch)
+	b.index.Delete(ch)
+	close(s.ch)
+}
@@ -0,0 +1,129 @@
+package chanx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lif0/pkg/chanx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receive reads one value from ch, failing the test if none arrives in time.
+func receive[T any](t *testing.T, ch <-chan T) (T, bool) {
+	t.Helper()
+
+	select {
+	case v, ok := <-ch:
+		return v, ok
+	case <-time.After(time.Second):
+		t.Fatal("no value received")
+		var zero T
+		return zero, false
+	}
+}
+
+// TestBroadcaster verifies that every subscriber receives every value and that the channels are closed with the input.
+func TestBroadcaster(t *testing.T) {
+	in := make(chan int)
+	b := chanx.NewBroadcaster(context.Background(), in)
+
+	subs := []<-chan int{b.Subscribe(), b.Subscribe(chanx.WithBuffer(10))}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i <= 3; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+
+	for _, values := range drainAll(subs) {
+		assert.Equal(t, []int{1, 2, 3}, values)
+	}
+	<-done
+
+	_, ok := receive(t, b.Subscribe())
+	assert.False(t, ok, "subscribing to a stopped broadcaster must return a closed channel")
+}
+
+// TestBroadcasterUnsubscribe verifies that Unsubscribe closes the channel, even while a send to it is blocked.
+func TestBroadcasterUnsubscribe(t *testing.T) {
+	in := make(chan int)
+	b := chanx.NewBroadcaster(context.Background(), in)
+	defer close(in)
+
+	stuck := b.Subscribe()
+	active := b.Subscribe(chanx.WithBuffer(10))
+
+	in <- 1 // the broadcaster is now blocked on stuck
+
+	b.Unsubscribe(stuck)
+	b.Unsubscribe(stuck) // no effect
+
+	in <- 2
+
+	v, _ := receive(t, active)
+	assert.Equal(t, 1, v)
+	v, _ = receive(t, active)
+	assert.Equal(t, 2, v)
+
+	for range stuck {
+		// stuck is closed; it may or may not have received 1
+	}
+}
+
+// TestBroadcasterPolicies verifies the slow-consumer policies with a subscriber that does not read.
+func TestBroadcasterPolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy chanx.SlowConsumerPolicy
+		want   []int
+	}{
+		{name: "drop newest", policy: chanx.DropNewest, want: []int{1, 2}},
+		{name: "drop oldest", policy: chanx.DropOldest, want: []int{4, 5}},
+		{name: "disconnect", policy: chanx.Disconnect, want: []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := make(chan int)
+			b := chanx.NewBroadcaster(context.Background(), in)
+
+			slow := b.Subscribe(chanx.WithBuffer(2), chanx.WithPolicy(tt.policy))
+			fast := b.Subscribe(chanx.WithBuffer(10))
+
+			for i := 1; i <= 5; i++ {
+				in <- i
+			}
+			for i := 1; i <= 5; i++ {
+				v, _ := receive(t, fast)
+				require.Equal(t, i, v, "a slow subscriber must not hold back the others")
+			}
+			close(in)
+
+			var got []int
+			for v := range slow {
+				got = append(got, v)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestBroadcasterContextCancel verifies that canceling the context closes every subscriber, even a blocked one.
+func TestBroadcasterContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	b := chanx.NewBroadcaster(ctx, in)
+
+	sub := b.Subscribe()
+	in <- 1 // blocked on sub
+
+	cancel()
+
+	for range sub {
+	}
+}
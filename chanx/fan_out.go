@@ -0,0 +1,194 @@
+package chanx
+
+import (
+	"context"
+	"hash/fnv"
+)
+
+// Strategy decides which output of FanOut receives the next value.
+type Strategy int
+
+const (
+	// RoundRobin sends values to the outputs in turn. It is the default.
+	RoundRobin Strategy = iota
+	// LeastBusy sends each value to the first output whose reader is ready for it,
+	// so a slow reader receives fewer values.
+	LeastBusy
+)
+
+// FanOutOption configures FanOut. It is either WithStrategy or WithBuffer.
+type FanOutOption interface {
+	applyFanOut(o *fanOutOptions)
+}
+
+type fanOutOptions struct {
+	options
+	strategy Strategy
+}
+
+type fanOutOptionFunc func(*fanOutOptions)
+
+func (f fanOutOptionFunc) applyFanOut(o *fanOutOptions) { f(o) }
+
+func (b BufferOption) applyFanOut(o *fanOutOptions) { b.apply(&o.options) }
+
+// WithStrategy sets how FanOut spreads values across its outputs. The default is RoundRobin.
+func WithStrategy(strategy Strategy) FanOutOption {
+	return fanOutOptionFunc(func(o *fanOutOptions) {
+		o.strategy = strategy
+	})
+}
+
+// FanOut spreads the values of in across n output channels, one value to one output,
+// so that n workers can share the load. It is the opposite of FanIn.
+// All outputs are closed when in is closed or the context is canceled.
+// If n is less than 1, FanOut returns no channels.
+// It accepts WithStrategy and WithBuffer.
+//
+// With RoundRobin, a reader that does not keep up blocks the others once its output is full.
+// With LeastBusy it does not: the other readers take over its share.
+//
+// Example usage:
+//
+//	for _, jobs := range chanx.FanOut(ctx, in, 4, chanx.WithStrategy(chanx.LeastBusy)) {
+//		go func() {
+//			for job := range jobs {
+//				process(job)
+//			}
+//		}()
+//	}
+func FanOut[T any](ctx context.Context, in <-chan T, n int, opts ...FanOutOption) []<-chan T {
+	if n < 1 {
+		return nil
+	}
+
+	var o fanOutOptions
+	for _, opt := range opts {
+		opt.applyFanOut(&o)
+	}
+
+	outs := makeChans[T](n, o.buffer)
+
+	if o.strategy == LeastBusy {
+		// Every output has its own goroutine pulling from in: the goroutine whose reader
+		// took the previous value first is the first to take the next one.
+		for _, out := range outs {
+			go func() {
+				defer close(out)
+				forward(ctx, in, out)
+			}()
+		}
+
+		return ToRecvChans(outs)
+	}
+
+	go func() {
+		defer closeAll(outs)
+
+		for i := 0; ; i = (i + 1) % n {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case outs[i] <- v:
+				}
+			}
+		}
+	}()
+
+	return ToRecvChans(outs)
+}
+
+// Partition routes the values of in to n output channels by key: the FNV-1a hash of keyFn(v)
+// picks the output, so values with the same key always go to the same output and are
+// processed in order by the same worker. All outputs are closed when in is closed or the
+// context is canceled. If n is less than 1, Partition returns no channels.
+// It accepts WithBuffer.
+//
+// Example usage:
+//
+//	shards := chanx.Partition(ctx, events, 8, func(e Event) string { return e.AccountID })
+//	for _, shard := range shards {
+//		go func() {
+//			for e := range shard {
+//				apply(e) // events of an account are applied in order
+//			}
+//		}()
+//	}
+func Partition[T any](ctx context.Context, in <-chan T, n int, keyFn func(T) string, opts ...Option) []<-chan T {
+	if n < 1 {
+		return nil
+	}
+
+	o := newOptions(opts)
+	outs := makeChans[T](n, o.buffer)
+
+	go func() {
+		defer closeAll(outs)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+
+				h := fnv.New64a()
+				_, _ = h.Write([]byte(keyFn(v)))
+				out := outs[h.Sum64()%uint64(n)] // #nosec G115 -- n is positive
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+	}()
+
+	return ToRecvChans(outs)
+}
+
+// forward sends the values of in to out until in is closed or the context is canceled.
+func forward[T any](ctx context.Context, in <-chan T, out chan<- T) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case v, ok := <-in:
+			if !ok {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- v:
+			}
+		}
+	}
+}
+
+func makeChans[T any](n, buffer int) []chan T {
+	chans := make([]chan T, n)
+	for i := range chans {
+		chans[i] = make(chan T, buffer)
+	}
+
+	return chans
+}
+
+func closeAll[T any](chans []chan T) {
+	for _, ch := range chans {
+		close(ch)
+	}
+}
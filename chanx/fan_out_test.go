@@ -0,0 +1,156 @@
+package chanx_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lif0/pkg/chanx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generate returns a channel that yields 1..n and is then closed.
+func generate(n int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 1; i <= n; i++ {
+			ch <- i
+		}
+	}()
+	return ch
+}
+
+// drainAll reads every output concurrently and returns the values received by each one.
+func drainAll[T any](outs []<-chan T) [][]T {
+	res := make([][]T, len(outs))
+	wg := sync.WaitGroup{}
+
+	for i, out := range outs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := range out {
+				res[i] = append(res[i], v)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return res
+}
+
+// TestFanOutRoundRobin verifies that FanOut hands values to the outputs in turn.
+func TestFanOutRoundRobin(t *testing.T) {
+	outs := chanx.FanOut(context.Background(), generate(9), 3)
+	require.Len(t, outs, 3)
+
+	res := drainAll(outs)
+
+	assert.Equal(t, []int{1, 4, 7}, res[0])
+	assert.Equal(t, []int{2, 5, 8}, res[1])
+	assert.Equal(t, []int{3, 6, 9}, res[2])
+}
+
+// TestFanOutLeastBusy verifies that FanOut delivers every value exactly once and that a slow reader gets fewer values.
+func TestFanOutLeastBusy(t *testing.T) {
+	outs := chanx.FanOut(context.Background(), generate(100), 2, chanx.WithStrategy(chanx.LeastBusy))
+	require.Len(t, outs, 2)
+
+	var fast []int
+	var slow int
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for v := range outs[0] {
+			fast = append(fast, v)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range outs[1] {
+			slow++
+			time.Sleep(time.Millisecond * 5)
+		}
+	}()
+
+	wg.Wait()
+
+	assert.Equal(t, 100, len(fast)+slow)
+	assert.Greater(t, len(fast), slow)
+}
+
+// TestFanOutContextCancel verifies that canceling the context closes every output.
+func TestFanOutContextCancel(t *testing.T) {
+	for _, strategy := range []chanx.Strategy{chanx.RoundRobin, chanx.LeastBusy} {
+		ctx, cancel := context.WithCancel(context.Background())
+		outs := chanx.FanOut(ctx, make(chan int), 3, chanx.WithStrategy(strategy))
+
+		cancel()
+
+		for _, out := range outs {
+			select {
+			case _, ok := <-out:
+				assert.False(t, ok)
+			case <-time.After(time.Second):
+				t.Fatal("output was not closed after cancel")
+			}
+		}
+	}
+}
+
+// TestFanOutZero verifies that FanOut with n < 1 returns no channels.
+func TestFanOutZero(t *testing.T) {
+	assert.Empty(t, chanx.FanOut(context.Background(), generate(1), 0))
+	assert.Empty(t, chanx.Partition(context.Background(), generate(1), 0, strconv.Itoa))
+}
+
+// TestPartition verifies that values with the same key always go to the same output, in order.
+func TestPartition(t *testing.T) {
+	key := func(v int) string { return strconv.Itoa(v % 5) }
+	outs := chanx.Partition(context.Background(), generate(100), 4, key, chanx.WithBuffer(10))
+	require.Len(t, outs, 4)
+
+	res := drainAll(outs)
+
+	owner := map[string]int{}
+	total := 0
+	for i, values := range res {
+		total += len(values)
+		for j, v := range values {
+			if prev, ok := owner[key(v)]; ok {
+				assert.Equal(t, prev, i, "key %s went to two outputs", key(v))
+			}
+			owner[key(v)] = i
+
+			if j > 0 {
+				assert.Less(t, values[j-1], v, "values of an output must keep their order")
+			}
+		}
+	}
+
+	assert.Equal(t, 100, total)
+	assert.Len(t, owner, 5)
+}
+
+// TestPartitionContextCancel verifies that canceling the context closes every output.
+func TestPartitionContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	outs := chanx.Partition(ctx, make(chan int), 2, strconv.Itoa)
+
+	cancel()
+
+	for _, out := range outs {
+		select {
+		case _, ok := <-out:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("output was not closed after cancel")
+		}
+	}
+}
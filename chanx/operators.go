@@ -0,0 +1,156 @@
+package chanx
+
+import "context"
+
+// Map sends fn(v) for every value v of in.
+// The output is closed when in is closed or the context is canceled.
+//
+// Example usage:
+//
+//	lengths := chanx.Map(ctx, words, func(w string) int { return len(w) })
+func Map[T, U any](ctx context.Context, in <-chan T, fn func(T) U) <-chan U {
+	return pipe(ctx, in, func(v T) (U, bool, bool) {
+		return fn(v), true, true
+	})
+}
+
+// FilterMap sends fn(v) for every value v of in for which fn reports true, and drops the others.
+// The output is closed when in is closed or the context is canceled.
+//
+// Example usage:
+//
+//	numbers := chanx.FilterMap(ctx, lines, func(s string) (int, bool) {
+//		n, err := strconv.Atoi(s)
+//		return n, err == nil
+//	})
+func FilterMap[T, U any](ctx context.Context, in <-chan T, fn func(T) (U, bool)) <-chan U {
+	return pipe(ctx, in, func(v T) (U, bool, bool) {
+		u, ok := fn(v)
+		return u, ok, true
+	})
+}
+
+// Filter sends the values of in for which pred reports true, and drops the others.
+// The output is closed when in is closed or the context is canceled.
+func Filter[T any](ctx context.Context, in <-chan T, pred func(T) bool) <-chan T {
+	return pipe(ctx, in, func(v T) (T, bool, bool) {
+		return v, pred(v), true
+	})
+}
+
+// Take sends the first n values of in and then closes the output without reading further.
+// The output is also closed when in is closed or the context is canceled.
+func Take[T any](ctx context.Context, in <-chan T, n int) <-chan T {
+	if n < 1 {
+		out := make(chan T)
+		close(out)
+		return out
+	}
+
+	taken := 0
+	return pipe(ctx, in, func(v T) (T, bool, bool) {
+		taken++
+		return v, true, taken < n
+	})
+}
+
+// TakeWhile sends the values of in as long as pred reports true, and closes the output
+// at the first value for which it reports false, without sending it.
+// The output is also closed when in is closed or the context is canceled.
+func TakeWhile[T any](ctx context.Context, in <-chan T, pred func(T) bool) <-chan T {
+	return pipe(ctx, in, func(v T) (T, bool, bool) {
+		ok := pred(v)
+		return v, ok, ok
+	})
+}
+
+// Skip drops the first n values of in and sends the rest.
+// The output is closed when in is closed or the context is canceled.
+func Skip[T any](ctx context.Context, in <-chan T, n int) <-chan T {
+	skipped := 0
+	return pipe(ctx, in, func(v T) (T, bool, bool) {
+		if skipped < n {
+			skipped++
+			return v, false, true
+		}
+		return v, true, true
+	})
+}
+
+// Distinct sends every value of in the first time it is seen and drops its repetitions.
+// It remembers every value sent, so its memory grows with the number of distinct values.
+// The output is closed when in is closed or the context is canceled.
+func Distinct[T comparable](ctx context.Context, in <-chan T) <-chan T {
+	return DistinctBy(ctx, in, func(v T) T { return v })
+}
+
+// DistinctBy sends every value of in whose key has not been seen before and drops the others.
+// It remembers every key seen, so its memory grows with the number of distinct keys.
+// The output is closed when in is closed or the context is canceled.
+//
+// Example usage:
+//
+//	firstVisits := chanx.DistinctBy(ctx, visits, func(v Visit) string { return v.UserID })
+func DistinctBy[T any, K comparable](ctx context.Context, in <-chan T, key func(T) K) <-chan T {
+	seen := make(map[K]struct{})
+	return pipe(ctx, in, func(v T) (T, bool, bool) {
+		k := key(v)
+		if _, ok := seen[k]; ok {
+			return v, false, true
+		}
+		seen[k] = struct{}{}
+		return v, true, true
+	})
+}
+
+// Scan sends the running accumulation of the values of in: for every value v it sets
+// acc = fn(acc, v), starting from init, and sends acc.
+// The output is closed when in is closed or the context is canceled.
+//
+// Example usage:
+//
+//	totals := chanx.Scan(ctx, amounts, 0, func(sum, amount int) int { return sum + amount })
+func Scan[T, A any](ctx context.Context, in <-chan T, init A, fn func(A, T) A) <-chan A {
+	acc := init
+	return pipe(ctx, in, func(v T) (A, bool, bool) {
+		acc = fn(acc, v)
+		return acc, true, true
+	})
+}
+
+// pipe runs step on every value of in in a new goroutine. step returns the value to send,
+// whether to send it, and whether to go on reading in. The output is closed when in is closed,
+// step stops the stage or the context is canceled.
+func pipe[T, U any](ctx context.Context, in <-chan T, step func(T) (U, bool, bool)) <-chan U {
+	out := make(chan U)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+
+				u, send, more := step(v)
+				if send {
+					select {
+					case <-ctx.Done():
+						return
+					case out <- u:
+					}
+				}
+
+				if !more {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,150 @@
+package chanx_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lif0/pkg/chanx"
+	"github.com/stretchr/testify/assert"
+)
+
+// collect reads ch until it is closed and returns the values.
+func collect[T any](ch <-chan T) []T {
+	var res []T
+	for v := range ch {
+		res = append(res, v)
+	}
+	return res
+}
+
+// slice returns a closed, buffered channel holding values.
+func slice[T any](values ...T) <-chan T {
+	ch := make(chan T, len(values))
+	for _, v := range values {
+		ch <- v
+	}
+	close(ch)
+	return ch
+}
+
+// TestOperators verifies the output of every operator.
+func TestOperators(t *testing.T) {
+	ctx := context.Background()
+	even := func(v int) bool { return v%2 == 0 }
+
+	t.Run("Map", func(t *testing.T) {
+		assert.Equal(t, []string{"1", "2", "3"}, collect(chanx.Map(ctx, generate(3), strconv.Itoa)))
+	})
+
+	t.Run("FilterMap", func(t *testing.T) {
+		out := chanx.FilterMap(ctx, slice("1", "x", "3"), func(s string) (int, bool) {
+			n, err := strconv.Atoi(s)
+			return n, err == nil
+		})
+		assert.Equal(t, []int{1, 3}, collect(out))
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		assert.Equal(t, []int{2, 4, 6}, collect(chanx.Filter(ctx, generate(6), even)))
+	})
+
+	t.Run("Take", func(t *testing.T) {
+		assert.Equal(t, []int{1, 2, 3}, collect(chanx.Take(ctx, generate(10), 3)))
+		assert.Equal(t, []int{1, 2}, collect(chanx.Take(ctx, generate(2), 5)))
+		assert.Empty(t, collect(chanx.Take(ctx, generate(2), 0)))
+	})
+
+	t.Run("Take stops reading", func(t *testing.T) {
+		in := make(chan int, 5)
+		for i := 1; i <= 5; i++ {
+			in <- i
+		}
+
+		assert.Equal(t, []int{1, 2}, collect(chanx.Take(ctx, in, 2)))
+		assert.Len(t, in, 3, "Take must not consume values beyond n")
+	})
+
+	t.Run("TakeWhile", func(t *testing.T) {
+		assert.Equal(t, []int{2, 4}, collect(chanx.TakeWhile(ctx, slice(2, 4, 5, 6), even)))
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		assert.Equal(t, []int{4, 5}, collect(chanx.Skip(ctx, generate(5), 3)))
+		assert.Equal(t, []int{1, 2}, collect(chanx.Skip(ctx, generate(2), 0)))
+	})
+
+	t.Run("Distinct", func(t *testing.T) {
+		assert.Equal(t, []int{1, 2, 3}, collect(chanx.Distinct(ctx, slice(1, 2, 1, 3, 2, 3))))
+	})
+
+	t.Run("DistinctBy", func(t *testing.T) {
+		out := chanx.DistinctBy(ctx, slice("apple", "avocado", "banana", "blueberry", "cherry"), func(s string) byte { return s[0] })
+		assert.Equal(t, []string{"apple", "banana", "cherry"}, collect(out))
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		out := chanx.Scan(ctx, generate(4), 0, func(sum, v int) int { return sum + v })
+		assert.Equal(t, []int{1, 3, 6, 10}, collect(out))
+	})
+
+	t.Run("pipeline", func(t *testing.T) {
+		squares := chanx.Map(ctx, chanx.Filter(ctx, generate(10), even), func(v int) int { return v * v })
+		assert.Equal(t, []int{4, 16, 36}, collect(chanx.Take(ctx, squares, 3)))
+	})
+}
+
+// TestOperatorsContextCancel verifies that every operator closes its output when the context is canceled,
+// both while waiting for input and while waiting for a reader.
+func TestOperatorsContextCancel(t *testing.T) {
+	stages := map[string]func(ctx context.Context, in <-chan int) <-chan int{
+		"Map": func(ctx context.Context, in <-chan int) <-chan int {
+			return chanx.Map(ctx, in, func(v int) int { return v })
+		},
+		"FilterMap": func(ctx context.Context, in <-chan int) <-chan int {
+			return chanx.FilterMap(ctx, in, func(v int) (int, bool) { return v, true })
+		},
+		"Filter": func(ctx context.Context, in <-chan int) <-chan int {
+			return chanx.Filter(ctx, in, func(int) bool { return true })
+		},
+		"Take": func(ctx context.Context, in <-chan int) <-chan int { return chanx.Take(ctx, in, 10) },
+		"TakeWhile": func(ctx context.Context, in <-chan int) <-chan int {
+			return chanx.TakeWhile(ctx, in, func(int) bool { return true })
+		},
+		"Skip":     func(ctx context.Context, in <-chan int) <-chan int { return chanx.Skip(ctx, in, 0) },
+		"Distinct": func(ctx context.Context, in <-chan int) <-chan int { return chanx.Distinct(ctx, in) },
+		"Scan": func(ctx context.Context, in <-chan int) <-chan int {
+			return chanx.Scan(ctx, in, 0, func(a, v int) int { return a + v })
+		},
+	}
+
+	for name, stage := range stages {
+		t.Run(name, func(t *testing.T) {
+			for _, in := range []<-chan int{make(chan int), slice(1)} {
+				ctx, cancel := context.WithCancel(context.Background())
+				out := stage(ctx, in)
+
+				time.Sleep(time.Millisecond) // let the stage block on in or on out
+				cancel()
+
+				select {
+				case <-time.After(time.Second):
+					t.Fatal("output was not closed after cancel")
+				case <-closed(out):
+				}
+			}
+		})
+	}
+}
+
+// closed returns a channel that is closed once ch is closed, discarding the values of ch.
+func closed[T any](ch <-chan T) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range ch {
+		}
+	}()
+	return done
+}
@@ -0,0 +1,36 @@
+package chanx
+
+// Option configures the channel stages of this package that have no settings of their own,
+// such as Partition and Tee. Stages with settings of their own, such as FanOut, Batch or
+// ParallelMap, have an option type of their own; every option type accepts WithBuffer.
+type Option interface {
+	apply(o *options)
+}
+
+type options struct {
+	buffer int
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return o
+}
+
+// BufferOption sets the buffer size of the output channels of a stage. It is created by WithBuffer
+// and is accepted by every stage, whatever its option type.
+type BufferOption struct {
+	size int
+}
+
+// WithBuffer sets the buffer size of the output channels of a stage. The default is 0, unbuffered.
+func WithBuffer(size int) BufferOption {
+	return BufferOption{size: max(size, 0)}
+}
+
+func (b BufferOption) apply(o *options) {
+	o.buffer = b.size
+}
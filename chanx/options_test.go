@@ -0,0 +1,38 @@
+package chanx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lif0/pkg/chanx"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithBuffer verifies that WithBuffer sets the buffer of the outputs of every stage,
+// whatever its option type, and that a negative size means unbuffered.
+func TestWithBuffer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	buf := chanx.WithBuffer(3)
+
+	for _, out := range chanx.FanOut(ctx, in, 2, buf) {
+		assert.Equal(t, 3, cap(out), "FanOut")
+	}
+	for _, out := range chanx.Partition(ctx, in, 2, func(int) string { return "" }, buf) {
+		assert.Equal(t, 3, cap(out), "Partition")
+	}
+	for _, out := range chanx.Tee(ctx, in, 2, buf) {
+		assert.Equal(t, 3, cap(out), "Tee")
+	}
+
+	assert.Equal(t, 3, cap(chanx.NewBroadcaster(ctx, in).Subscribe(buf)), "Subscribe")
+	assert.Equal(t, 3, cap(chanx.Batch(ctx, in, 10, time.Second, buf)), "Batch")
+
+	out, _ := chanx.ParallelMap(ctx, in, 2, func(_ context.Context, v int) (int, error) { return v, nil }, buf)
+	assert.Equal(t, 3, cap(out), "ParallelMap")
+
+	assert.Equal(t, 0, cap(chanx.Tee(ctx, in, 1, chanx.WithBuffer(-1))[0]))
+}
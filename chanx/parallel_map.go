@@ -0,0 +1,267 @@
+package chanx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/lif0/pkg/errx"
+)
+
+// ParallelMapOption configures ParallelMap. It is WithOrdered, WithFailFast or WithBuffer.
+type ParallelMapOption interface {
+	applyParallelMap(o *parallelMapOptions)
+}
+
+type parallelMapOptions struct {
+	options
+	window   int // reorder window; 0 means unordered
+	failFast bool
+}
+
+type parallelMapOptionFunc func(*parallelMapOptions)
+
+func (f parallelMapOptionFunc) applyParallelMap(o *parallelMapOptions) { f(o) }
+
+func (b BufferOption) applyParallelMap(o *parallelMapOptions) { b.apply(&o.options) }
+
+// WithOrdered makes ParallelMap emit its results in the order of the input. Results that finish
+// ahead of an earlier one wait in a reorder buffer of at most window results; while it is full,
+// no new value is started. A window smaller than the number of workers is raised to it.
+func WithOrdered(window int) ParallelMapOption {
+	return parallelMapOptionFunc(func(o *parallelMapOptions) {
+		o.window = max(window, 1)
+	})
+}
+
+// WithFailFast makes the first error of ParallelMap cancel the stage: the context passed to fn
+// is canceled, the output is closed, and only that error is reported.
+func WithFailFast() ParallelMapOption {
+	return parallelMapOptionFunc(func(o *parallelMapOptions) {
+		o.failFast = true
+	})
+}
+
+// ParallelMap sends fn(ctx, v) for every value v of in, running fn on up to workers goroutines
+// at once. If workers is less than 1, it is treated as 1. The output is closed when in is closed
+// or the context is canceled and every started call has returned.
+// By default results are sent as they finish, in no particular order; WithOrdered keeps the input order.
+//
+// A value for which fn fails is dropped. The returned function waits for the output to be closed
+// and returns the errors of fn as an errx.MultiError, or nil if there were none; with WithFailFast
+// the first error cancels the stage and is the only one returned. If the context ends the stage,
+// context.Cause(ctx) is returned with them, so that a truncated run is not mistaken for a complete one.
+// Read the output until it is closed before calling it. It accepts WithOrdered, WithFailFast and WithBuffer.
+//
+// Example usage:
+//
+//	parsed, wait := chanx.ParallelMap(ctx, lines, 8, func(ctx context.Context, line string) (Entry, error) {
+//		return parse(line)
+//	}, chanx.WithOrdered(64))
+//
+//	for e := range parsed {
+//		write(e) // in the order of lines
+//	}
+//	if err := wait(); err != nil {
+//		log.Println(err)
+//	}
+func ParallelMap[T, U any](ctx context.Context, in <-chan T, workers int, fn func(ctx context.Context, v T) (U, error), opts ...ParallelMapOption) (<-chan U, func() error) {
+	var o parallelMapOptions
+	for _, opt := range opts {
+		opt.applyParallelMap(&o)
+	}
+
+	workers = max(workers, 1)
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	p := &parallelMap[T, U]{
+		parent:   parent,
+		ctx:      ctx,
+		cancel:   cancel,
+		fn:       fn,
+		failFast: o.failFast,
+		out:      make(chan U, o.buffer),
+		done:     make(chan struct{}),
+	}
+
+	if o.window > 0 {
+		go p.runOrdered(in, workers, max(o.window, workers))
+	} else {
+		go p.run(in, workers)
+	}
+
+	return p.out, p.wait
+}
+
+type parallelMap[T, U any] struct {
+	parent   context.Context
+	ctx      context.Context
+	cancel   context.CancelFunc
+	fn       func(ctx context.Context, v T) (U, error)
+	failFast bool
+	out      chan U
+	done     chan struct{}
+
+	mu   sync.Mutex
+	errs errx.MultiError
+}
+
+type sequenced[V any] struct {
+	seq int
+	v   V
+	ok  bool
+}
+
+func (p *parallelMap[T, U]) run(in <-chan T, workers int) {
+	defer p.finish()
+
+	wg := sync.WaitGroup{}
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				v, ok := p.receive(in)
+				if !ok {
+					return
+				}
+
+				if u, ok := p.call(v); ok && !send(p.ctx, p.out, u) {
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (p *parallelMap[T, U]) runOrdered(in <-chan T, workers, window int) {
+	defer p.finish()
+
+	var (
+		tasks   = make(chan sequenced[T])
+		results = make(chan sequenced[U])
+		slots   = make(chan struct{}, window) // a slot is held from dispatch until the result is sent
+		wg      sync.WaitGroup
+	)
+
+	// On cancellation the collector stops early; let the running calls return before closing the output.
+	defer wg.Wait()
+
+	go func() {
+		defer close(tasks)
+
+		for seq := 0; ; seq++ {
+			v, ok := p.receive(in)
+			if !ok || !send(p.ctx, slots, struct{}{}) || !send(p.ctx, tasks, sequenced[T]{seq: seq, v: v}) {
+				return
+			}
+		}
+	}()
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for t := range tasks {
+				u, ok := p.call(t.v)
+				if !send(p.ctx, results, sequenced[U]{seq: t.seq, v: u, ok: ok}) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]sequenced[U], window)
+	next := 0
+
+	for r := range results {
+		pending[r.seq] = r
+
+		for r, ok := pending[next]; ok; r, ok = pending[next] {
+			if r.ok && !send(p.ctx, p.out, r.v) {
+				return
+			}
+
+			delete(pending, next)
+			next++
+			<-slots
+		}
+	}
+}
+
+// receive reads the next value of in, unless in is closed or the context is canceled.
+func (p *parallelMap[T, U]) receive(in <-chan T) (T, bool) {
+	select {
+	case <-p.ctx.Done():
+		var zero T
+		return zero, false
+	case v, ok := <-in:
+		return v, ok
+	}
+}
+
+// call runs fn on v and records its error. It reports whether fn succeeded.
+func (p *parallelMap[T, U]) call(v T) (U, bool) {
+	u, err := p.fn(p.ctx, v)
+	if err == nil {
+		return u, true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.errs.Append(err)
+	if p.failFast {
+		p.cancel()
+	}
+
+	return u, false
+}
+
+func (p *parallelMap[T, U]) finish() {
+	if err := context.Cause(p.parent); err != nil {
+		p.mu.Lock()
+		p.errs.Append(err) // the stage was ended by its context
+		p.mu.Unlock()
+	}
+
+	close(p.out)
+	p.cancel()
+	close(p.done)
+}
+
+func (p *parallelMap[T, U]) wait() error {
+	<-p.done
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.errs.IsEmpty() {
+		return nil
+	}
+
+	if p.failFast {
+		return p.errs[0]
+	}
+
+	return p.errs
+}
+
+// send sends v on ch, unless the context is canceled first.
+func send[V any](ctx context.Context, ch chan<- V, v V) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case ch <- v:
+		return true
+	}
+}
@@ -0,0 +1,193 @@
+package chanx_test
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lif0/pkg/chanx"
+	"github.com/lif0/pkg/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jitter sleeps for up to a millisecond, so that calls finish out of order.
+func jitter() {
+	time.Sleep(time.Duration(rand.IntN(1000)) * time.Microsecond) // #nosec G404 -- test delay
+}
+
+// TestParallelMap verifies that every value is mapped and that fn runs on at most workers goroutines.
+func TestParallelMap(t *testing.T) {
+	var running, peak atomic.Int32
+
+	out, wait := chanx.ParallelMap(context.Background(), generate(100), 4, func(_ context.Context, v int) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+
+		jitter()
+		return v * 2, nil
+	})
+
+	res := collect(out)
+	require.NoError(t, wait())
+
+	sort.Ints(res)
+	want := make([]int, 100)
+	for i := range want {
+		want[i] = (i + 1) * 2
+	}
+	assert.Equal(t, want, res)
+	assert.LessOrEqual(t, peak.Load(), int32(4))
+}
+
+// TestParallelMapOrdered verifies that WithOrdered keeps the input order despite random delays.
+func TestParallelMapOrdered(t *testing.T) {
+	out, wait := chanx.ParallelMap(context.Background(), generate(200), 8, func(_ context.Context, v int) (int, error) {
+		jitter()
+		return v, nil
+	}, chanx.WithOrdered(16))
+
+	res := collect(out)
+	require.NoError(t, wait())
+
+	want := collect(generate(200))
+	assert.Equal(t, want, res)
+}
+
+// TestParallelMapOrderedWindow verifies that while the first value is in progress,
+// no more than window values are started.
+func TestParallelMapOrderedWindow(t *testing.T) {
+	release := make(chan struct{})
+	var started atomic.Int32
+
+	out, wait := chanx.ParallelMap(context.Background(), generate(20), 4, func(_ context.Context, v int) (int, error) {
+		started.Add(1)
+		if v == 1 {
+			<-release
+		}
+		return v, nil
+	}, chanx.WithOrdered(6))
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(6), started.Load(), "the reorder window must bound the values started")
+
+	close(release)
+	assert.Equal(t, collect(generate(20)), collect(out))
+	require.NoError(t, wait())
+}
+
+// TestParallelMapErrors verifies that failed values are dropped and their errors collected.
+func TestParallelMapErrors(t *testing.T) {
+	for _, opts := range [][]chanx.ParallelMapOption{nil, {chanx.WithOrdered(4)}} {
+		out, wait := chanx.ParallelMap(context.Background(), generate(10), 3, func(_ context.Context, v int) (int, error) {
+			if v%3 == 0 {
+				return 0, errors.New("multiple of 3")
+			}
+			return v, nil
+		}, opts...)
+
+		res := collect(out)
+		sort.Ints(res)
+		assert.Equal(t, []int{1, 2, 4, 5, 7, 8, 10}, res)
+
+		err := wait()
+		var errs errx.MultiError
+		require.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 3)
+	}
+}
+
+// TestParallelMapFailFast verifies that WithFailFast cancels the stage at the first error and returns only it.
+func TestParallelMapFailFast(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	for _, opts := range [][]chanx.ParallelMapOption{{chanx.WithFailFast()}, {chanx.WithFailFast(), chanx.WithOrdered(4)}} {
+		in := make(chan int) // never closed
+		go func() {
+			for i := 1; ; i++ {
+				select {
+				case in <- i:
+				case <-time.After(time.Second):
+					return
+				}
+			}
+		}()
+
+		out, wait := chanx.ParallelMap(context.Background(), in, 4, func(ctx context.Context, v int) (int, error) {
+			if v == 5 {
+				return 0, errBoom
+			}
+
+			select {
+			case <-ctx.Done(): // canceled by the failure
+			case <-time.After(time.Millisecond):
+			}
+			return v, nil
+		}, opts...)
+
+		select {
+		case <-time.After(time.Second):
+			t.Fatal("output was not closed after the first error")
+		case <-closed(out):
+		}
+
+		assert.Equal(t, errBoom, wait())
+	}
+}
+
+// TestParallelMapContextCancel verifies that the output is closed when the context is canceled,
+// both while waiting for input and while waiting for a reader, and that wait reports the cancellation.
+func TestParallelMapContextCancel(t *testing.T) {
+	identity := func(_ context.Context, v int) (int, error) { return v, nil }
+
+	for _, opts := range [][]chanx.ParallelMapOption{nil, {chanx.WithOrdered(4)}} {
+		for _, in := range []<-chan int{make(chan int), slice(1, 2, 3)} {
+			ctx, cancel := context.WithCancel(context.Background())
+			out, wait := chanx.ParallelMap(ctx, in, 2, identity, opts...)
+
+			time.Sleep(time.Millisecond) // let the stage block on in or on out
+			cancel()
+
+			select {
+			case <-time.After(time.Second):
+				t.Fatal("output was not closed after cancel")
+			case <-closed(out):
+			}
+
+			assert.ErrorIs(t, wait(), context.Canceled)
+		}
+	}
+}
+
+// TestParallelMapContextCause verifies that wait returns the cause of the cancellation
+// together with the errors of fn.
+func TestParallelMapContextCause(t *testing.T) {
+	errShutdown := errors.New("shutdown")
+	errBad := errors.New("bad value")
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	in := make(chan int) // never closed
+	out, wait := chanx.ParallelMap(ctx, in, 2, func(_ context.Context, v int) (int, error) {
+		if v == 1 {
+			return 0, errBad
+		}
+		return v, nil
+	})
+
+	in <- 1
+	in <- 2
+	<-out
+	cancel(errShutdown)
+	<-closed(out)
+
+	err := wait()
+	assert.ErrorIs(t, err, errShutdown)
+	assert.ErrorIs(t, err, errBad)
+}
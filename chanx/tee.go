@@ -0,0 +1,48 @@
+package chanx
+
+import "context"
+
+// Tee copies every value of in to each of n output channels, so that n readers all see
+// the whole stream. A value is sent to the outputs one after another, so the slowest reader
+// sets the pace; use WithBuffer to absorb bursts, or a Broadcaster to decouple the readers.
+// All outputs are closed when in is closed or the context is canceled.
+// If n is less than 1, Tee returns no channels. It accepts WithBuffer.
+//
+// Example usage:
+//
+//	outs := chanx.Tee(ctx, events, 2)
+//	go audit(outs[0])
+//	go index(outs[1])
+func Tee[T any](ctx context.Context, in <-chan T, n int, opts ...Option) []<-chan T {
+	if n < 1 {
+		return nil
+	}
+
+	o := newOptions(opts)
+	outs := makeChans[T](n, o.buffer)
+
+	go func() {
+		defer closeAll(outs)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+
+				for _, out := range outs {
+					select {
+					case <-ctx.Done():
+						return
+					case out <- v:
+					}
+				}
+			}
+		}
+	}()
+
+	return ToRecvChans(outs)
+}
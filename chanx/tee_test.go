@@ -0,0 +1,43 @@
+package chanx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lif0/pkg/chanx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTee verifies that every output of Tee receives every value, in order.
+func TestTee(t *testing.T) {
+	outs := chanx.Tee(context.Background(), generate(5), 3, chanx.WithBuffer(1))
+	require.Len(t, outs, 3)
+
+	for _, values := range drainAll(outs) {
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, values)
+	}
+}
+
+// TestTeeZero verifies that Tee with n < 1 returns no channels.
+func TestTeeZero(t *testing.T) {
+	assert.Empty(t, chanx.Tee(context.Background(), generate(1), 0))
+}
+
+// TestTeeContextCancel verifies that canceling the context closes every output.
+func TestTeeContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	outs := chanx.Tee(ctx, make(chan int), 2)
+
+	cancel()
+
+	for _, out := range outs {
+		select {
+		case _, ok := <-out:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("output was not closed after cancel")
+		}
+	}
+}
@@ -30,6 +30,12 @@ func (errs *MultiError) Append(err error) {
 	}
 }
 
+// Unwrap returns the contained errors, so that errors.Is and errors.As
+// match any of them.
+func (errs MultiError) Unwrap() []error {
+	return errs
+}
+
 // MaybeUnwrap returns nil if len(errs) is 0. It returns the first and only
 // contained error as error if len(errs is 1). In all other cases, it returns
 // the MultiError directly. This is helpful for returning a MultiError in a way
@@ -116,6 +116,24 @@ func TestMultiError_MaybeUnwrap(t *testing.T) {
 	}
 }
 
+type codeError struct{ code int }
+
+func (e *codeError) Error() string { return "code error" }
+
+func TestMultiError_Unwrap(t *testing.T) {
+	err1 := errors.New("error one")
+	err2 := &codeError{code: 2}
+	var err error = errx.MultiError{err1, err2}
+
+	assert.ErrorIs(t, err, err1)
+	assert.NotErrorIs(t, err, errors.New("error one"))
+
+	var target *codeError
+	if assert.ErrorAs(t, err, &target) {
+		assert.Equal(t, 2, target.code)
+	}
+}
+
 var globalErr = errx.MultiError{}
 
 func TestMultiError_Other(t *testing.T) {